@@ -1,12 +1,26 @@
 package main
 
 import (
+	"Pull-Requests-master/internal/assignment"
+	"Pull-Requests-master/internal/codehost"
+	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/handlers"
 	"Pull-Requests-master/internal/migration"
+	"Pull-Requests-master/internal/notify"
+	"Pull-Requests-master/internal/outbound"
+	"Pull-Requests-master/internal/outbox"
+	"Pull-Requests-master/internal/scheduler"
+	"Pull-Requests-master/internal/service"
+	"Pull-Requests-master/internal/slack"
+	"Pull-Requests-master/internal/telegram"
 	"Pull-Requests-master/package/config"
 	"Pull-Requests-master/package/database"
 	"Pull-Requests-master/package/logger"
+	"context"
 	"fmt"
+	"os"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/labstack/echo/v4"
 )
@@ -38,26 +52,222 @@ func main() {
 	}
 	log.Info("migration completed")
 
-	handler := handlers.NewHandler(db, log)
+	hours := assignment.DefaultWorkingHours()
+	if config.Assignment.WorkStart != "" && config.Assignment.WorkEnd != "" {
+		hours.Start = config.Assignment.WorkStart
+		hours.End = config.Assignment.WorkEnd
+	}
+	if config.Assignment.TimeZone != "" {
+		hours.Location, err = time.LoadLocation(config.Assignment.TimeZone)
+		if err != nil {
+			log.Fatalf("bad assignment time zone: %v", err)
+		}
+	}
+	strategies := map[string]assignment.Strategy{}
+	for _, name := range assignment.Names() {
+		strategy, err := assignment.New(name, hours)
+		if err != nil {
+			log.Fatalf("assignment strategy wasn't created: %v", err)
+		}
+		if config.Assignment.MatchLabels {
+			strategy = assignment.WithLabels(strategy)
+		}
+		strategies[name] = assignment.WithExclusions(assignment.WithSeniority(strategy))
+	}
+	if config.Assignment.Strategy == "" {
+		config.Assignment.Strategy = assignment.StrategyRandom
+	}
+	strategy, ok := strategies[config.Assignment.Strategy]
+	if !ok {
+		log.Fatalf("unknown assignment strategy: %s", config.Assignment.Strategy)
+	}
+
+	codeHost := service.CodeHostConfig{MaxAttempts: config.CodeHost.GitHub.MaxAttempts}
+	if config.CodeHost.GitHub.PushReviewers {
+		codeHost.Client = codehost.NewGitHub(config.CodeHost.GitHub.BaseURL, config.CodeHost.GitHub.Token)
+	}
+
+	outboundCfg := service.OutboundConfig{MaxAttempts: config.Outbound.MaxAttempts}
+	if config.Outbound.Enabled {
+		outboundCfg.Sender = outbound.NewHTTP(config.Outbound.Timeout)
+	}
+
+	outboxCfg := service.OutboxConfig{Retention: config.Outbox.Retention}
+	switch config.Outbox.Sink {
+	case "":
+	case outbox.SinkStdout:
+		outboxCfg.Sink = outbox.NewStdout(os.Stdout)
+	case outbox.SinkHTTP:
+		outboxCfg.Sink = outbox.NewHTTP(config.Outbox.HTTP.URL, config.Outbox.Timeout)
+	case outbox.SinkNATS:
+		outboxCfg.Sink, err = outbox.NewNATS(config.Outbox.NATS.URL, config.Outbox.NATS.Subject, config.Outbox.Timeout)
+		if err != nil {
+			log.Fatalf("outbox sink wasn't created: %v", err)
+		}
+	default:
+		log.Fatalf("unknown outbox sink: %s", config.Outbox.Sink)
+	}
+
+	notifyCfg := service.NotifyConfig{Channels: map[string]notify.Channel{}}
+	if config.Notifications.Email.Enabled {
+		email := config.Notifications.Email
+		notifyCfg.Channels[domain.ChannelEmail] = notify.NewEmail(notify.EmailConfig{
+			Host:     email.Host,
+			Port:     email.Port,
+			Username: email.Username,
+			Password: email.Password,
+			From:     email.From,
+			StartTLS: email.StartTLS,
+			Timeout:  email.Timeout,
+		})
+	}
+
+	slackCfg := service.SlackConfig{
+		Webhooks:      config.Slack.Webhooks,
+		SigningSecret: config.Slack.SigningSecret,
+	}
+	if config.Slack.Enabled {
+		slackCfg.Poster = slack.NewHTTP(config.Slack.Timeout)
+	}
+
+	var telegramClient *telegram.Client
+	telegramCfg := service.TelegramConfig{}
+	if config.Telegram.Enabled {
+		telegramClient = telegram.NewClient(config.Telegram.APIURL, config.Telegram.Token, config.Telegram.Timeout)
+		telegramCfg.Sender = telegramClient
+	}
+
+	svc := service.NewService(db, log, service.Config{
+		Strategy:      strategy,
+		Strategies:    strategies,
+		Clock:         assignment.SystemClock{},
+		Hours:         hours,
+		ReviewSLA:     config.Assignment.ReviewSLA,
+		PairingWindow: config.Assignment.PairingWindow,
+		GitHub: service.WebhookConfig{
+			Secret: config.Webhooks.GitHub.Secret,
+			Users:  config.Webhooks.GitHub.Users,
+		},
+		GitLab: service.WebhookConfig{
+			Secret:  config.Webhooks.GitLab.Token,
+			Users:   config.Webhooks.GitLab.Users,
+			UserIDs: config.Webhooks.GitLab.UserIDs,
+		},
+		CodeHost: codeHost,
+		Outbound: outboundCfg,
+		Outbox:   outboxCfg,
+		Notify:   notifyCfg,
+		Slack:    slackCfg,
+		Telegram: telegramCfg,
+	})
+	if config.Scheduler.Enabled {
+		sch := scheduler.New(svc, log, config.Scheduler.Interval, config.Scheduler.ReassignOnAway)
+		go sch.Run(context.Background())
+		log.Info("scheduler was started")
+	}
+
+	if codeHost.Client != nil {
+		retrier := scheduler.NewRetrier("code host jobs", svc.ProcessCodeHostJobs, svc.CodeHostQueued(),
+			log, config.CodeHost.GitHub.RetryInterval)
+		go retrier.Run(context.Background())
+		log.Info("code host retrier was started")
+	}
+
+	if outboundCfg.Sender != nil {
+		retrier := scheduler.NewRetrier("webhook deliveries", svc.ProcessWebhookDeliveries, svc.DeliveriesQueued(),
+			log, config.Outbound.RetryInterval)
+		go retrier.Run(context.Background())
+		log.Info("webhook delivery retrier was started")
+	}
+
+	if outboxCfg.Sink != nil {
+		relay := scheduler.NewRelay(svc, log, config.Outbox.Interval)
+		go relay.Run(context.Background())
+		log.Infof("outbox relay to %s was started", config.Outbox.Sink)
+	}
+
+	if telegramClient != nil {
+		bot := telegram.NewBot(telegramClient, svc, log, config.Telegram.PollTimeout)
+		go bot.Run(context.Background())
+		log.Info("telegram bot was started")
+	}
+
+	handler := handlers.NewHandler(svc, log)
 	e := echo.New()
 
 	teams := e.Group("/team")
 	{
 		teams.POST("/add", handler.AddTeam)
 		teams.GET("/get", handler.GetTeam)
+		teams.POST("/policy", handler.SetTeamPolicy)
+		teams.GET("/pairings", handler.GetPairings)
+		teams.POST("/holidays", handler.ImportHolidays)
+		teams.GET("/holidays", handler.GetHolidays)
 	}
 
 	users := e.Group("/users")
 	{
 		users.POST("/setIsActive", handler.SetUserActive)
 		users.GET("/getReview", handler.GetUserReview)
+		users.GET("/get", handler.GetUser)
+		users.PATCH("", handler.UpdateUserProfile)
+		users.POST("/away", handler.AddAwayPeriod)
+		users.GET("/away", handler.GetAwayPeriods)
+		users.DELETE("/away", handler.DeleteAwayPeriod)
+		users.GET("/notifications", handler.GetNotificationPreferences)
+		users.POST("/notifications", handler.SetNotificationPreferences)
 	}
 
 	pullRequests := e.Group("/pullRequest")
 	{
 		pullRequests.POST("/create", handler.CreatePR)
+		pullRequests.POST("/previewAssignment", handler.PreviewAssignment)
 		pullRequests.POST("/merge", handler.MergePR)
 		pullRequests.POST("/reassign", handler.ReassignReviewersPR)
+		pullRequests.GET("/assignments", handler.GetAssignments)
+	}
+	repositories := e.Group("/repositories")
+	{
+		repositories.POST("/add", handler.AddRepository)
+		repositories.POST("/update", handler.UpdateRepository)
+		repositories.GET("/get", handler.GetRepository)
+	}
+
+	reviewerGroups := e.Group("/reviewerGroups")
+	{
+		reviewerGroups.POST("", handler.SaveReviewerGroup)
+		reviewerGroups.GET("", handler.GetReviewerGroups)
+		reviewerGroups.DELETE("", handler.DeleteReviewerGroup)
+	}
+
+	exclusions := e.Group("/exclusions")
+	{
+		exclusions.POST("", handler.AddExclusionRule)
+		exclusions.GET("", handler.GetExclusionRules)
+		exclusions.DELETE("", handler.DeleteExclusionRule)
+	}
+
+	webhooks := e.Group("/webhooks")
+	{
+		webhooks.POST("/github", handler.GitHubWebhook)
+		webhooks.POST("/gitlab", handler.GitLabWebhook)
+	}
+
+	subscriptions := e.Group("/subscriptions")
+	{
+		subscriptions.POST("", handler.AddSubscription)
+		subscriptions.GET("", handler.GetSubscriptions)
+		subscriptions.DELETE("", handler.DeleteSubscription)
+		subscriptions.GET("/deliveries", handler.GetDeliveries)
+		subscriptions.POST("/deliveries/replay", handler.ReplayDelivery)
+	}
+
+	e.POST("/slack/commands", handler.SlackCommand)
+
+	codeOwners := e.Group("/codeowners")
+	{
+		codeOwners.POST("/upload", handler.UploadCodeOwners)
+		codeOwners.GET("/get", handler.GetCodeOwners)
 	}
 	e.Start(":8080")
 
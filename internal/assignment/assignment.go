@@ -0,0 +1,125 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// MaxReviewers is how many reviewers a new pull request gets when the team is large enough.
+const MaxReviewers = 2
+
+const (
+	StrategyRandom       = "random"
+	StrategyWorkingHours = "working_hours"
+	StrategyDiversity    = "diversity"
+)
+
+// Clock lets tests pin the moment assignment decisions are made at.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Request is the input of a single reviewer selection.
+type Request struct {
+	Candidates []*domain.User
+	Count      int
+	Now        time.Time
+	Holidays   Holidays
+	// Labels of the pull request that reviewers' skills should cover.
+	Labels []string
+	// Existing reviewers of the pull request count towards the seniority rule.
+	Existing   []*domain.User
+	Seniority  SeniorityRule
+	Exclusions Exclusions
+	// Pairings counts recent reviews of the author's pull requests per candidate id.
+	Pairings map[string]int
+}
+
+// Strategy picks up to Count reviewers out of the eligible candidates.
+type Strategy interface {
+	Name() string
+	Select(req Request) []*domain.User
+}
+
+// Names lists the strategies New knows.
+func Names() []string {
+	return []string{StrategyRandom, StrategyWorkingHours, StrategyDiversity}
+}
+
+func New(name string, hours WorkingHours) (Strategy, error) {
+	switch name {
+	case "", StrategyRandom:
+		return &randomStrategy{hours: hours}, nil
+	case StrategyWorkingHours:
+		return &workingHoursStrategy{hours: hours}, nil
+	case StrategyDiversity:
+		return &diversityStrategy{hours: hours}, nil
+	default:
+		return nil, fmt.Errorf("unknown assignment strategy: %s", name)
+	}
+}
+
+// randomStrategy picks reviewers at random, skipping those whose team is on holiday
+// today unless nobody else is left.
+type randomStrategy struct {
+	hours WorkingHours
+}
+
+func (s *randomStrategy) Name() string { return StrategyRandom }
+
+func (s *randomStrategy) Select(req Request) []*domain.User {
+	return head(shuffle(notOnHoliday(req, s.hours)), req.Count)
+}
+
+// notOnHoliday drops candidates whose team is on holiday today, unless that drops everyone.
+func notOnHoliday(req Request, hours WorkingHours) []*domain.User {
+	working := []*domain.User{}
+	for _, c := range req.Candidates {
+		local := req.Now.In(hours.For(c).Location)
+		if !req.Holidays.Off(c.TeamName, local) {
+			working = append(working, c)
+		}
+	}
+	if len(working) == 0 {
+		return req.Candidates
+	}
+	return working
+}
+
+// Exclude returns the candidates whose ids are not in ids.
+func Exclude(candidates []*domain.User, ids ...string) []*domain.User {
+	excluded := map[string]bool{}
+	for _, id := range ids {
+		excluded[id] = true
+	}
+
+	result := []*domain.User{}
+	for _, c := range candidates {
+		if !excluded[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func head(candidates []*domain.User, count int) []*domain.User {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	return candidates[:count]
+}
+
+func shuffle(candidates []*domain.User) []*domain.User {
+	shuffled := make([]*domain.User, len(candidates))
+	copy(shuffled, candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
@@ -0,0 +1,36 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// diversityStrategy picks at random like randomStrategy, but a candidate who recently
+// reviewed the author n times is 1+n times less likely to be picked, so that knowledge of
+// an author's code spreads across the team.
+type diversityStrategy struct {
+	hours WorkingHours
+}
+
+func (s *diversityStrategy) Name() string { return StrategyDiversity }
+
+func (s *diversityStrategy) Select(req Request) []*domain.User {
+	candidates := notOnHoliday(req, s.hours)
+
+	// Weighted sampling without replacement: the largest u^(1/w) keys win.
+	keys := make(map[string]float64, len(candidates))
+	for _, c := range candidates {
+		weight := 1 / float64(1+req.Pairings[c.ID])
+		keys[c.ID] = math.Pow(rand.Float64(), 1/weight)
+	}
+
+	sorted := make([]*domain.User, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i].ID] > keys[sorted[j].ID]
+	})
+
+	return head(sorted, req.Count)
+}
@@ -0,0 +1,37 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiversityStrategy_Select(t *testing.T) {
+	strategy, err := New(StrategyDiversity, DefaultWorkingHours())
+	require.NoError(t, err)
+	assert.Equal(t, StrategyDiversity, strategy.Name())
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	candidates := []*domain.User{skilled("frequent"), skilled("rare")}
+
+	t.Run("without history every candidate is picked", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now})
+		assert.ElementsMatch(t, []string{"frequent", "rare"}, ids(picked))
+	})
+
+	t.Run("frequent pairings are down-weighted", func(t *testing.T) {
+		counts := map[string]int{}
+		for i := 0; i < 2000; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 1, Now: now,
+				Pairings: map[string]int{"frequent": 9}})
+			require.Len(t, picked, 1)
+			counts[picked[0].ID]++
+		}
+		// With weights 1/10 and 1 the rare reviewer wins about 10 times out of 11.
+		assert.Greater(t, counts["rare"], counts["frequent"]*4)
+		assert.Greater(t, counts["frequent"], 0)
+	})
+}
@@ -0,0 +1,130 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+)
+
+// Exclusions answers whether two people may be put on the same review.
+// The zero value allows everything.
+type Exclusions struct {
+	rules []*domain.ExclusionRule
+}
+
+func NewExclusions(rules []*domain.ExclusionRule) Exclusions {
+	return Exclusions{rules: rules}
+}
+
+// Together returns the rule forbidding a and b to review the same pull request, or nil.
+func (e Exclusions) Together(a, b string) *domain.ExclusionRule {
+	return e.find(domain.ExclusionNotTogether, a, b)
+}
+
+// CrossReview returns the rule forbidding the reviewer to review the author, or nil.
+func (e Exclusions) CrossReview(authorID, reviewerID string) *domain.ExclusionRule {
+	return e.find(domain.ExclusionNoCrossReview, authorID, reviewerID)
+}
+
+func (e Exclusions) find(kind, a, b string) *domain.ExclusionRule {
+	if a == b {
+		return nil
+	}
+	for _, rule := range e.rules {
+		if rule.Kind == kind && contains(rule.Members, a) && contains(rule.Members, b) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Filter drops the candidates who may not review one of the authors of the pull request or
+// may not sit next to one of its existing reviewers, and reports why.
+func (e Exclusions) Filter(candidates []*domain.User, authorIDs []string, existing []string) ([]*domain.User, []*domain.ExcludedCandidate) {
+	kept := []*domain.User{}
+	excluded := []*domain.ExcludedCandidate{}
+	for _, c := range candidates {
+		var rule *domain.ExclusionRule
+		for _, id := range authorIDs {
+			if rule != nil {
+				break
+			}
+			rule = e.CrossReview(id, c.ID)
+		}
+		for _, id := range existing {
+			if rule != nil {
+				break
+			}
+			rule = e.Together(id, c.ID)
+		}
+		if rule != nil {
+			excluded = append(excluded, &domain.ExcludedCandidate{UserID: c.ID, RuleID: rule.ID})
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, excluded
+}
+
+// WithExclusions wraps a strategy so that no two of its picks are kept apart by a
+// not_together rule: a conflicting pick is swapped for another candidate chosen by base,
+// or dropped if nobody fits.
+func WithExclusions(base Strategy) Strategy {
+	return &exclusionStrategy{base: base}
+}
+
+type exclusionStrategy struct {
+	base Strategy
+}
+
+func (s *exclusionStrategy) Name() string { return s.base.Name() }
+
+func (s *exclusionStrategy) Select(req Request) []*domain.User {
+	picked := s.base.Select(req)
+	if len(req.Exclusions.rules) == 0 {
+		return picked
+	}
+
+	tried := userIDs(picked)
+	result := []*domain.User{}
+	for _, p := range picked {
+		if !s.conflicts(req.Exclusions, result, p) {
+			result = append(result, p)
+			continue
+		}
+
+		rest := []*domain.User{}
+		for _, c := range Exclude(req.Candidates, tried...) {
+			if !s.conflicts(req.Exclusions, result, c) {
+				rest = append(rest, c)
+			}
+		}
+		if len(rest) == 0 {
+			continue
+		}
+		sub := req
+		sub.Candidates = rest
+		sub.Count = 1
+		if chosen := s.base.Select(sub); len(chosen) > 0 {
+			tried = append(tried, chosen[0].ID)
+			result = append(result, chosen[0])
+		}
+	}
+	return result
+}
+
+func (s *exclusionStrategy) conflicts(e Exclusions, picked []*domain.User, user *domain.User) bool {
+	for _, p := range picked {
+		if e.Together(p.ID, user.ID) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusions_Filter(t *testing.T) {
+	ex := NewExclusions([]*domain.ExclusionRule{
+		{ID: 1, Kind: domain.ExclusionNoCrossReview, Members: []string{"alice", "manager"}},
+		{ID: 2, Kind: domain.ExclusionNotTogether, Members: []string{"bob", "carol", "dave"}},
+	})
+	candidates := []*domain.User{skilled("manager"), skilled("carol"), skilled("erin")}
+
+	kept, excluded := ex.Filter(candidates, []string{"alice"}, []string{"bob"})
+
+	assert.Equal(t, []string{"erin"}, ids(kept))
+	require.Len(t, excluded, 2)
+	assert.Equal(t, &domain.ExcludedCandidate{UserID: "manager", RuleID: 1}, excluded[0])
+	assert.Equal(t, &domain.ExcludedCandidate{UserID: "carol", RuleID: 2}, excluded[1])
+}
+
+func TestExclusions_ZeroValue(t *testing.T) {
+	var ex Exclusions
+	kept, excluded := ex.Filter([]*domain.User{skilled("a")}, []string{"b"}, []string{"c"})
+	assert.Len(t, kept, 1)
+	assert.Empty(t, excluded)
+	assert.Nil(t, ex.Together("a", "c"))
+}
+
+func TestExclusionStrategy_Select(t *testing.T) {
+	base, err := New(StrategyRandom, DefaultWorkingHours())
+	require.NoError(t, err)
+	strategy := WithExclusions(base)
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	ex := NewExclusions([]*domain.ExclusionRule{
+		{ID: 2, Kind: domain.ExclusionNotTogether, Members: []string{"bob", "carol"}},
+	})
+
+	t.Run("conflicting pick is replaced", func(t *testing.T) {
+		candidates := []*domain.User{skilled("bob"), skilled("carol"), skilled("erin")}
+		for i := 0; i < 20; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now, Exclusions: ex})
+			require.Len(t, picked, 2)
+			assert.Contains(t, ids(picked), "erin")
+		}
+	})
+
+	t.Run("conflicting pick is dropped when nobody else fits", func(t *testing.T) {
+		candidates := []*domain.User{skilled("bob"), skilled("carol")}
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now, Exclusions: ex})
+		assert.Len(t, picked, 1)
+	})
+}
@@ -0,0 +1,34 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Holidays maps a team name to the set of its days off keyed by "2006-01-02".
+type Holidays map[string]map[string]bool
+
+func NewHolidays(holidays []*domain.Holiday) Holidays {
+	result := Holidays{}
+	for _, h := range holidays {
+		if result[h.TeamName] == nil {
+			result[h.TeamName] = map[string]bool{}
+		}
+		result[h.TeamName][h.Date] = true
+	}
+	return result
+}
+
+// Off reports whether the local calendar date of day is a holiday of the team.
+func (h Holidays) Off(team string, day time.Time) bool {
+	return h[team][day.Format(dateLayout)]
+}
+
+// DayOff returns a filter for WorkingHours that skips the team's holidays.
+func (h Holidays) DayOff(team string) func(day time.Time) bool {
+	return func(day time.Time) bool {
+		return h.Off(team, day)
+	}
+}
@@ -0,0 +1,84 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+)
+
+const StrategyLabelMatch = "label_match"
+
+// WithLabels wraps a strategy so that every label of the pull request is covered by
+// at least one reviewer with a matching skill, even if that takes more than Count reviewers.
+// The remaining slots are filled by base.
+func WithLabels(base Strategy) Strategy {
+	return &labelStrategy{base: base}
+}
+
+type labelStrategy struct {
+	base Strategy
+}
+
+func (s *labelStrategy) Name() string { return StrategyLabelMatch + "+" + s.base.Name() }
+
+func (s *labelStrategy) Select(req Request) []*domain.User {
+	picked := []*domain.User{}
+	rest := req.Candidates
+	uncovered := UncoveredLabels(req.Labels, nil)
+
+	// Greedily take the candidate covering most of the uncovered labels; among equally
+	// good candidates the base strategy decides. Labels nobody has a skill for are skipped.
+	for len(uncovered) > 0 && len(rest) > 0 {
+		best := []*domain.User{}
+		bestCovered := 0
+		for _, c := range rest {
+			covered := len(uncovered) - len(UncoveredLabels(uncovered, []*domain.User{c}))
+			if covered == 0 || covered < bestCovered {
+				continue
+			}
+			if covered > bestCovered {
+				best = best[:0]
+				bestCovered = covered
+			}
+			best = append(best, c)
+		}
+		if len(best) == 0 {
+			break
+		}
+
+		sub := req
+		sub.Candidates = best
+		sub.Count = 1
+		chosen := s.base.Select(sub)
+		if len(chosen) == 0 {
+			break
+		}
+		picked = append(picked, chosen[0])
+		rest = Exclude(rest, chosen[0].ID)
+		uncovered = UncoveredLabels(uncovered, chosen)
+	}
+
+	if len(picked) < req.Count {
+		sub := req
+		sub.Candidates = rest
+		sub.Count = req.Count - len(picked)
+		picked = append(picked, s.base.Select(sub)...)
+	}
+	return picked
+}
+
+// UncoveredLabels returns the labels for which none of the reviewers has a matching skill.
+func UncoveredLabels(labels []string, reviewers []*domain.User) []string {
+	skills := map[string]bool{}
+	for _, r := range reviewers {
+		for _, skill := range r.Skills {
+			skills[skill] = true
+		}
+	}
+
+	result := []string{}
+	for _, label := range labels {
+		if !skills[label] {
+			result = append(result, label)
+		}
+	}
+	return result
+}
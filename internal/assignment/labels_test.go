@@ -0,0 +1,76 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skilled(id string, skills ...string) *domain.User {
+	return &domain.User{
+		Member:  domain.Member{ID: id, IsActive: true},
+		Profile: domain.Profile{Skills: skills},
+	}
+}
+
+func ids(users []*domain.User) []string {
+	result := []string{}
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+	return result
+}
+
+func TestLabelStrategy_Select(t *testing.T) {
+	base, err := New(StrategyRandom, DefaultWorkingHours())
+	require.NoError(t, err)
+	strategy := WithLabels(base)
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	candidates := []*domain.User{
+		skilled("dba", "db"),
+		skilled("sec", "security"),
+		skilled("full", "db", "security"),
+		skilled("front", "frontend"),
+		skilled("plain"),
+	}
+
+	t.Run("no labels delegates to base", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now})
+		assert.Len(t, picked, 2)
+	})
+
+	t.Run("one reviewer covering several labels is preferred", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now, Labels: []string{"db", "security"}})
+			require.Len(t, picked, 2)
+			assert.Equal(t, "full", picked[0].ID)
+			assert.Empty(t, UncoveredLabels([]string{"db", "security"}, picked))
+		}
+	})
+
+	t.Run("every label gets a reviewer even beyond count", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 1, Now: now, Labels: []string{"frontend", "db"}})
+			require.Len(t, picked, 2)
+			assert.Contains(t, ids(picked), "front")
+			assert.Empty(t, UncoveredLabels([]string{"frontend", "db"}, picked))
+		}
+	})
+
+	t.Run("labels nobody knows are skipped", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now, Labels: []string{"mobile", "frontend"}})
+		require.Len(t, picked, 2)
+		assert.Equal(t, "front", picked[0].ID)
+		assert.NotContains(t, ids(picked[1:]), "front")
+	})
+}
+
+func TestUncoveredLabels(t *testing.T) {
+	reviewers := []*domain.User{skilled("a", "db"), skilled("b", "frontend")}
+	assert.Equal(t, []string{"security"}, UncoveredLabels([]string{"db", "security", "frontend"}, reviewers))
+	assert.Equal(t, []string{"db"}, UncoveredLabels([]string{"db"}, nil))
+}
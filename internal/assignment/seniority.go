@@ -0,0 +1,123 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+)
+
+// SeniorityRule is the reviewer mix a team asks for.
+type SeniorityRule struct {
+	// RequireSenior asks for at least one senior reviewer when the team has one available.
+	RequireSenior bool
+	// NoTwoJuniors drops a second junior reviewer when nobody else can take the place.
+	NoTwoJuniors bool
+}
+
+// WithSeniority wraps a strategy so that its picks satisfy req.Seniority: a senior replaces
+// one of the picks if none was chosen, and extra juniors are swapped for other candidates.
+// Replacements are chosen by base.
+func WithSeniority(base Strategy) Strategy {
+	return &seniorityStrategy{base: base}
+}
+
+type seniorityStrategy struct {
+	base Strategy
+}
+
+func (s *seniorityStrategy) Name() string { return s.base.Name() }
+
+func (s *seniorityStrategy) Select(req Request) []*domain.User {
+	picked := s.base.Select(req)
+	rule := req.Seniority
+
+	hasSenior := hasSeniority(req.Existing, domain.SenioritySenior) || hasSeniority(picked, domain.SenioritySenior)
+	if rule.RequireSenior && req.Count > 0 && !hasSenior {
+		seniors := withSeniority(Exclude(req.Candidates, userIDs(picked)...), domain.SenioritySenior)
+		if senior := s.pickOne(req, seniors); senior != nil {
+			picked = replaceOne(picked, senior, req.Count)
+		}
+	}
+
+	if rule.NoTwoJuniors {
+		juniors := len(withSeniority(req.Existing, domain.SeniorityJunior))
+		result := []*domain.User{}
+		for i, p := range picked {
+			if p.Seniority != domain.SeniorityJunior {
+				result = append(result, p)
+				continue
+			}
+			if juniors == 0 {
+				juniors++
+				result = append(result, p)
+				continue
+			}
+
+			rest := Exclude(req.Candidates, userIDs(append(result, picked[i:]...))...)
+			others := []*domain.User{}
+			for _, c := range rest {
+				if c.Seniority != domain.SeniorityJunior {
+					others = append(others, c)
+				}
+			}
+			if other := s.pickOne(req, others); other != nil {
+				result = append(result, other)
+			}
+		}
+		picked = result
+	}
+
+	return picked
+}
+
+func (s *seniorityStrategy) pickOne(req Request, candidates []*domain.User) *domain.User {
+	if len(candidates) == 0 {
+		return nil
+	}
+	sub := req
+	sub.Candidates = candidates
+	sub.Count = 1
+	sub.Labels = nil
+	chosen := s.base.Select(sub)
+	if len(chosen) == 0 {
+		return nil
+	}
+	return chosen[0]
+}
+
+// replaceOne puts the user in place of the last junior, or the last pick if there is none.
+func replaceOne(picked []*domain.User, user *domain.User, count int) []*domain.User {
+	if len(picked) < count {
+		return append(picked, user)
+	}
+	result := append([]*domain.User{}, picked...)
+	at := len(result) - 1
+	for i := len(result) - 1; i >= 0; i-- {
+		if result[i].Seniority == domain.SeniorityJunior {
+			at = i
+			break
+		}
+	}
+	result[at] = user
+	return result
+}
+
+func hasSeniority(users []*domain.User, seniority string) bool {
+	return len(withSeniority(users, seniority)) > 0
+}
+
+func withSeniority(users []*domain.User, seniority string) []*domain.User {
+	result := []*domain.User{}
+	for _, u := range users {
+		if u.Seniority == seniority {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+func userIDs(users []*domain.User) []string {
+	result := []string{}
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+	return result
+}
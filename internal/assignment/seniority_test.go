@@ -0,0 +1,80 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ranked(id, seniority string) *domain.User {
+	return &domain.User{
+		Member:  domain.Member{ID: id, IsActive: true},
+		Profile: domain.Profile{Seniority: seniority},
+	}
+}
+
+func TestSeniorityStrategy_Select(t *testing.T) {
+	base, err := New(StrategyRandom, DefaultWorkingHours())
+	require.NoError(t, err)
+	strategy := WithSeniority(base)
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	candidates := []*domain.User{
+		ranked("j1", domain.SeniorityJunior),
+		ranked("j2", domain.SeniorityJunior),
+		ranked("j3", domain.SeniorityJunior),
+		ranked("m1", domain.SeniorityMiddle),
+		ranked("s1", domain.SenioritySenior),
+	}
+
+	t.Run("no rule delegates to base", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now})
+		assert.Len(t, picked, 2)
+	})
+
+	t.Run("a senior is always picked", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now,
+				Seniority: SeniorityRule{RequireSenior: true}})
+			require.Len(t, picked, 2)
+			assert.Contains(t, ids(picked), "s1")
+		}
+	})
+
+	t.Run("an existing senior is enough", func(t *testing.T) {
+		juniors := candidates[:3]
+		picked := strategy.Select(Request{Candidates: juniors, Count: 1, Now: now,
+			Existing:  []*domain.User{ranked("s2", domain.SenioritySenior)},
+			Seniority: SeniorityRule{RequireSenior: true}})
+		require.Len(t, picked, 1)
+		assert.Equal(t, domain.SeniorityJunior, picked[0].Seniority)
+	})
+
+	t.Run("never two juniors", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 3, Now: now,
+				Seniority: SeniorityRule{NoTwoJuniors: true}})
+			require.Len(t, picked, 3)
+			assert.LessOrEqual(t, len(withSeniority(picked, domain.SeniorityJunior)), 1)
+		}
+	})
+
+	t.Run("extra junior is dropped when nobody else is left", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates[:3], Count: 2, Now: now,
+			Seniority: SeniorityRule{NoTwoJuniors: true}})
+		assert.Len(t, picked, 1)
+	})
+
+	t.Run("both rules", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: now,
+				Existing:  []*domain.User{ranked("j0", domain.SeniorityJunior)},
+				Seniority: SeniorityRule{RequireSenior: true, NoTwoJuniors: true}})
+			require.Len(t, picked, 2)
+			assert.ElementsMatch(t, []string{"m1", "s1"}, ids(picked))
+		}
+	})
+}
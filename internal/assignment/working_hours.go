@@ -0,0 +1,165 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// never is returned by Until when no working window is found within the lookahead.
+const never = time.Duration(1<<63 - 1)
+
+const lookaheadDays = 45
+
+// WorkingHours describes a recurring weekly working window in a given location.
+// Start and End are "HH:MM"; End not after Start means the shift ends the next day.
+type WorkingHours struct {
+	Start    string
+	End      string
+	Days     []time.Weekday
+	Location *time.Location
+}
+
+func DefaultWorkingHours() WorkingHours {
+	return WorkingHours{
+		Start:    "09:00",
+		End:      "18:00",
+		Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Location: time.UTC,
+	}
+}
+
+// ParseClock parses "HH:MM" into minutes since midnight.
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("bad time of day %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// For returns the working hours of the user, taking unset fields from h.
+func (h WorkingHours) For(user *domain.User) WorkingHours {
+	result := h
+	if user.WorkStart != "" {
+		result.Start = user.WorkStart
+	}
+	if user.WorkEnd != "" {
+		result.End = user.WorkEnd
+	}
+	if len(user.WorkDays) > 0 {
+		result.Days = make([]time.Weekday, 0, len(user.WorkDays))
+		for _, day := range user.WorkDays {
+			result.Days = append(result.Days, time.Weekday(day))
+		}
+	}
+	if user.TimeZone != "" {
+		if loc, err := time.LoadLocation(user.TimeZone); err == nil {
+			result.Location = loc
+		}
+	}
+	return result
+}
+
+// Until returns how long it takes from now until the next working window opens,
+// or zero when now is already inside one. dayOff, if set, skips whole local days.
+func (h WorkingHours) Until(now time.Time, dayOff func(day time.Time) bool) time.Duration {
+	start, _, ok := h.window(now, dayOff)
+	if !ok {
+		return never
+	}
+	if start.After(now) {
+		return start.Sub(now)
+	}
+	return 0
+}
+
+// Add returns the moment when d of working time has passed since start,
+// or the zero time if the working windows run out.
+func (h WorkingHours) Add(start time.Time, d time.Duration, dayOff func(day time.Time) bool) time.Time {
+	t := start
+	for d > 0 {
+		windowStart, windowEnd, ok := h.window(t, dayOff)
+		if !ok {
+			return time.Time{}
+		}
+		if windowStart.After(t) {
+			t = windowStart
+		}
+		if available := windowEnd.Sub(t); d > available {
+			d -= available
+			t = windowEnd
+			continue
+		}
+		t = t.Add(d)
+		d = 0
+	}
+	return t
+}
+
+// window returns the working window containing t or, if there is none, the next one.
+func (h WorkingHours) window(t time.Time, dayOff func(day time.Time) bool) (time.Time, time.Time, bool) {
+	start, err := ParseClock(h.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := ParseClock(h.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	local := t.In(loc)
+	// Start from yesterday so that an overnight shift covering t is found;
+	// look far enough ahead to get over long holidays.
+	for d := -1; d <= lookaheadDays; d++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, loc)
+		if !h.worksOn(day.Weekday()) || (dayOff != nil && dayOff(day)) {
+			continue
+		}
+
+		windowStart := day.Add(time.Duration(start) * time.Minute)
+		windowEnd := day.Add(time.Duration(end) * time.Minute)
+		if end <= start {
+			windowEnd = windowEnd.Add(24 * time.Hour)
+		}
+		if windowEnd.After(local) {
+			return windowStart, windowEnd, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func (h WorkingHours) worksOn(day time.Weekday) bool {
+	for _, d := range h.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// workingHoursStrategy prefers reviewers who are at work right now, then those who start soonest.
+type workingHoursStrategy struct {
+	hours WorkingHours
+}
+
+func (s *workingHoursStrategy) Name() string { return StrategyWorkingHours }
+
+func (s *workingHoursStrategy) Select(req Request) []*domain.User {
+	// Shuffle first so that candidates with the same availability are picked at random.
+	shuffled := shuffle(req.Candidates)
+	until := make(map[string]time.Duration, len(shuffled))
+	for _, c := range shuffled {
+		until[c.ID] = s.hours.For(c).Until(req.Now, req.Holidays.DayOff(c.TeamName))
+	}
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return until[shuffled[i].ID] < until[shuffled[j].ID]
+	})
+
+	return head(shuffled, req.Count)
+}
@@ -0,0 +1,167 @@
+package assignment
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func user(id, tz, start, end string) *domain.User {
+	return &domain.User{
+		Member:  domain.Member{ID: id, IsActive: true},
+		Profile: domain.Profile{TimeZone: tz, WorkStart: start, WorkEnd: end},
+	}
+}
+
+func TestWorkingHours_Until(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	hours := DefaultWorkingHours()
+	hours.Location = msk
+
+	t.Run("inside working hours", func(t *testing.T) {
+		// Monday 12:00 in Moscow.
+		now := time.Date(2025, 11, 3, 12, 0, 0, 0, msk)
+		assert.Equal(t, time.Duration(0), hours.Until(now, nil))
+	})
+
+	t.Run("after working hours waits for next morning", func(t *testing.T) {
+		// Monday 18:30 in Moscow, next window opens Tuesday 09:00.
+		now := time.Date(2025, 11, 3, 18, 30, 0, 0, msk)
+		assert.Equal(t, 14*time.Hour+30*time.Minute, hours.Until(now, nil))
+	})
+
+	t.Run("weekend waits for monday", func(t *testing.T) {
+		// Saturday 10:00 in Moscow.
+		now := time.Date(2025, 11, 8, 10, 0, 0, 0, msk)
+		assert.Equal(t, 47*time.Hour, hours.Until(now, nil))
+	})
+
+	t.Run("overnight shift covers early morning", func(t *testing.T) {
+		night := hours
+		night.Start, night.End = "22:00", "06:00"
+		// Tuesday 03:00 belongs to the shift that started on Monday.
+		now := time.Date(2025, 11, 4, 3, 0, 0, 0, msk)
+		assert.Equal(t, time.Duration(0), night.Until(now, nil))
+	})
+
+	t.Run("holidays are skipped", func(t *testing.T) {
+		holidays := NewHolidays([]*domain.Holiday{
+			{TeamName: "backend", Date: "2025-11-04"},
+		})
+		// Monday 18:30 in Moscow, Tuesday is a holiday so the next window is Wednesday 09:00.
+		now := time.Date(2025, 11, 3, 18, 30, 0, 0, msk)
+		assert.Equal(t, 38*time.Hour+30*time.Minute, hours.Until(now, holidays.DayOff("backend")))
+		assert.Equal(t, 14*time.Hour+30*time.Minute, hours.Until(now, holidays.DayOff("frontend")))
+	})
+
+	t.Run("user settings override defaults", func(t *testing.T) {
+		u := user("u1", "Asia/Novosibirsk", "08:00", "17:00")
+		u.WorkDays = []int64{1, 2, 3, 4, 5, 6}
+		h := hours.For(u)
+		assert.Equal(t, "Asia/Novosibirsk", h.Location.String())
+		assert.Equal(t, "08:00", h.Start)
+		assert.Len(t, h.Days, 6)
+	})
+}
+
+func TestWorkingHours_Add(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	hours := DefaultWorkingHours()
+	hours.Location = msk
+
+	t.Run("within the same day", func(t *testing.T) {
+		start := time.Date(2025, 11, 3, 10, 0, 0, 0, msk)
+		assert.Equal(t, time.Date(2025, 11, 3, 14, 0, 0, 0, msk), hours.Add(start, 4*time.Hour, nil))
+	})
+
+	t.Run("carries over to the next working day", func(t *testing.T) {
+		// Friday 16:00 + 8h of work is Monday 15:00.
+		start := time.Date(2025, 11, 7, 16, 0, 0, 0, msk)
+		assert.Equal(t, time.Date(2025, 11, 10, 15, 0, 0, 0, msk), hours.Add(start, 8*time.Hour, nil))
+	})
+
+	t.Run("starts counting at the next window and skips holidays", func(t *testing.T) {
+		holidays := NewHolidays([]*domain.Holiday{
+			{TeamName: "backend", Date: "2025-11-04"},
+		})
+		// Opened Monday night, Tuesday is off: the clock starts Wednesday 09:00.
+		start := time.Date(2025, 11, 3, 22, 0, 0, 0, msk)
+		assert.Equal(t, time.Date(2025, 11, 5, 11, 0, 0, 0, msk), hours.Add(start, 2*time.Hour, holidays.DayOff("backend")))
+	})
+}
+
+func TestRandomStrategy_SkipsHolidays(t *testing.T) {
+	strategy, err := New(StrategyRandom, DefaultWorkingHours())
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)
+	holidays := NewHolidays([]*domain.Holiday{{TeamName: "ru", Date: "2025-01-07", Name: "Christmas"}})
+	onHoliday := user("ru-dev", "", "", "")
+	onHoliday.TeamName = "ru"
+	working := user("contractor", "", "", "")
+	working.TeamName = "contractors"
+
+	for i := 0; i < 10; i++ {
+		picked := strategy.Select(Request{Candidates: []*domain.User{onHoliday, working}, Count: 1, Now: now, Holidays: holidays})
+		require.Len(t, picked, 1)
+		assert.Equal(t, "contractor", picked[0].ID)
+	}
+
+	picked := strategy.Select(Request{Candidates: []*domain.User{onHoliday}, Count: 1, Now: now, Holidays: holidays})
+	require.Len(t, picked, 1, "falls back to people on holiday when nobody else is left")
+}
+
+func TestWorkingHoursStrategy_Select(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	hours := DefaultWorkingHours()
+	hours.Location = msk
+
+	strategy, err := New(StrategyWorkingHours, hours)
+	require.NoError(t, err)
+
+	// Monday 18:00 in Moscow is 22:00 in Novosibirsk and 16:00 in London.
+	clock := fixedClock(time.Date(2025, 11, 3, 18, 0, 0, 0, msk))
+	candidates := []*domain.User{
+		user("nsk", "Asia/Novosibirsk", "", ""),
+		user("msk-late", "Europe/Moscow", "11:00", "20:00"),
+		user("london", "Europe/London", "", ""),
+		user("msk", "Europe/Moscow", "", ""),
+	}
+
+	t.Run("prefers reviewers currently at work", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 2, Now: clock.Now()})
+		require.Len(t, picked, 2)
+		assert.ElementsMatch(t, []string{"msk-late", "london"}, []string{picked[0].ID, picked[1].ID})
+	})
+
+	t.Run("falls back to whoever starts soonest", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates, Count: 3, Now: clock.Now()})
+		require.Len(t, picked, 3)
+		// Novosibirsk starts at 09:00 local (05:00 MSK), Moscow at 09:00 MSK.
+		assert.Equal(t, "nsk", picked[2].ID)
+	})
+
+	t.Run("count larger than candidates", func(t *testing.T) {
+		picked := strategy.Select(Request{Candidates: candidates[:1], Count: 2, Now: clock.Now()})
+		assert.Len(t, picked, 1)
+	})
+}
+
+func TestNew(t *testing.T) {
+	s, err := New("", DefaultWorkingHours())
+	require.NoError(t, err)
+	assert.Equal(t, StrategyRandom, s.Name())
+
+	_, err = New("round_robin", DefaultWorkingHours())
+	assert.Error(t, err)
+}
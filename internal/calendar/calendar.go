@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dateLayout = "2006-01-02"
+
+// ParseYAML reads a list of holidays:
+//
+//   - date: 2025-01-01
+//     name: New Year
+func ParseYAML(data []byte) ([]*domain.Holiday, error) {
+	var entries []struct {
+		Date string `yaml:"date"`
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %v", err)
+	}
+
+	holidays := []*domain.Holiday{}
+	for i, entry := range entries {
+		day, err := time.Parse(dateLayout, entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: bad date %q", i+1, entry.Date)
+		}
+		holidays = append(holidays, &domain.Holiday{Date: day.Format(dateLayout), Name: entry.Name})
+	}
+	return holidays, nil
+}
+
+// ParseICal reads all-day VEVENTs of an iCalendar file. Events spanning several days
+// (DTEND is exclusive) produce one holiday per day; events with a start time, such as
+// meetings, are skipped. Recurring events are refused rather than imported as their first
+// occurrence only.
+func ParseICal(data []byte) ([]*domain.Holiday, error) {
+	holidays := []*domain.Holiday{}
+	inEvent := false
+	allDay := false
+	var start, end, summary string
+
+	lines, err := unfold(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters such as ";VALUE=DATE".
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			allDay = false
+			start, end, summary = "", "", ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			if !allDay {
+				continue
+			}
+			days, err := eventDays(start, end)
+			if err != nil {
+				return nil, fmt.Errorf("event ending at line %d: %v", i+1, err)
+			}
+			for _, day := range days {
+				holidays = append(holidays, &domain.Holiday{Date: day, Name: summary})
+			}
+		case inEvent && name == "DTSTART":
+			start = value
+			// A DATE has no time part, a DATE-TIME is "20250101T090000".
+			allDay = !strings.Contains(value, "T")
+		case inEvent && name == "DTEND":
+			end = value
+		case inEvent && name == "SUMMARY":
+			summary = unescape(value)
+		case inEvent && (name == "RRULE" || name == "RDATE"):
+			return nil, fmt.Errorf("line %d: recurring events are not supported", i+1)
+		}
+	}
+	return holidays, nil
+}
+
+func eventDays(start, end string) ([]string, error) {
+	first, err := parseICalDate(start)
+	if err != nil {
+		return nil, err
+	}
+	last := first.AddDate(0, 0, 1)
+	if end != "" {
+		last, err = parseICalDate(end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	days := []string{}
+	for day := first; day.Before(last); day = day.AddDate(0, 0, 1) {
+		days = append(days, day.Format(dateLayout))
+	}
+	if len(days) == 0 {
+		days = append(days, first.Format(dateLayout))
+	}
+	return days, nil
+}
+
+// parseICalDate takes the date part of "20250101" or "20250101T000000Z".
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("bad date %q", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad date %q", value)
+	}
+	return day, nil
+}
+
+// maxLine bounds a physical line; exporters don't always fold long DESCRIPTIONs.
+const maxLine = 1 << 20
+
+// unfold joins continuation lines, which start with a space or a tab.
+func unfold(data []byte) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %v", err)
+	}
+	return lines, nil
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYAML(t *testing.T) {
+	t.Run("list of holidays", func(t *testing.T) {
+		data := []byte(`
+- date: 2025-01-01
+  name: New Year
+- date: "2025-01-07"
+  name: Christmas
+`)
+		holidays, err := ParseYAML(data)
+
+		require.NoError(t, err)
+		require.Len(t, holidays, 2)
+		assert.Equal(t, "2025-01-01", holidays[0].Date)
+		assert.Equal(t, "New Year", holidays[0].Name)
+		assert.Equal(t, "2025-01-07", holidays[1].Date)
+	})
+
+	t.Run("bad date", func(t *testing.T) {
+		_, err := ParseYAML([]byte(`- date: 01.01.2025`))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseICal(t *testing.T) {
+	t.Run("all-day and multi-day events, timed ones skipped", func(t *testing.T) {
+		data := []byte("BEGIN:VCALENDAR\r\n" +
+			"VERSION:2.0\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART;VALUE=DATE:20250101\r\n" +
+			"DTEND;VALUE=DATE:20250104\r\n" +
+			"SUMMARY:New Year\r\n" +
+			"  holidays\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART;VALUE=DATE:20250612\r\n" +
+			"SUMMARY:Russia Day\\, public holiday\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART:20250613T090000Z\r\n" +
+			"DTEND:20250613T100000Z\r\n" +
+			"SUMMARY:Planning\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n")
+
+		holidays, err := ParseICal(data)
+
+		require.NoError(t, err)
+		require.Len(t, holidays, 4)
+		assert.Equal(t, "2025-01-01", holidays[0].Date)
+		assert.Equal(t, "2025-01-03", holidays[2].Date)
+		assert.Equal(t, "New Year holidays", holidays[0].Name)
+		assert.Equal(t, "2025-06-12", holidays[3].Date)
+		assert.Equal(t, "Russia Day, public holiday", holidays[3].Name)
+	})
+
+	t.Run("bad date", func(t *testing.T) {
+		data := []byte("BEGIN:VEVENT\nDTSTART:2025\nEND:VEVENT\n")
+		_, err := ParseICal(data)
+		assert.Error(t, err)
+	})
+
+	t.Run("long unfolded line", func(t *testing.T) {
+		data := []byte("BEGIN:VEVENT\n" +
+			"DTSTART;VALUE=DATE:20250101\n" +
+			"DESCRIPTION:" + strings.Repeat("x", 100*1024) + "\n" +
+			"SUMMARY:New Year\n" +
+			"END:VEVENT\n")
+		holidays, err := ParseICal(data)
+		require.NoError(t, err)
+		require.Len(t, holidays, 1)
+		assert.Equal(t, "New Year", holidays[0].Name)
+	})
+
+	t.Run("line too long", func(t *testing.T) {
+		data := []byte("BEGIN:VEVENT\nDESCRIPTION:" + strings.Repeat("x", maxLine) + "\nEND:VEVENT\n")
+		_, err := ParseICal(data)
+		assert.Error(t, err)
+	})
+
+	t.Run("recurring event", func(t *testing.T) {
+		data := []byte("BEGIN:VEVENT\n" +
+			"DTSTART;VALUE=DATE:20250101\n" +
+			"RRULE:FREQ=YEARLY\n" +
+			"SUMMARY:New Year\n" +
+			"END:VEVENT\n")
+		_, err := ParseICal(data)
+		assert.ErrorContains(t, err, "recurring")
+	})
+}
@@ -0,0 +1,93 @@
+// Package codehost talks back to the code hosts pull requests come from.
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reviewers changes who is asked to review a pull request on the code host.
+type Reviewers interface {
+	RequestReviewers(ctx context.Context, repo string, number int, logins []string) error
+	RemoveReviewers(ctx context.Context, repo string, number int, logins []string) error
+}
+
+// DefaultGitHubURL is the API of github.com; GitHub Enterprise lives under /api/v3 of its host.
+const DefaultGitHubURL = "https://api.github.com"
+
+// GitHub requests and removes pull request reviewers through the REST API.
+type GitHub struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewGitHub(baseURL string, token string) *GitHub {
+	if baseURL == "" {
+		baseURL = DefaultGitHubURL
+	}
+	return &GitHub{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ParseGitHubID splits a pull request id of the GitHub webhook, "owner/repo#number".
+func ParseGitHubID(id string) (repo string, number int, ok bool) {
+	i := strings.LastIndex(id, "#")
+	if i < 0 || !strings.Contains(id[:i], "/") {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(id[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return id[:i], number, true
+}
+
+func (g *GitHub) RequestReviewers(ctx context.Context, repo string, number int, logins []string) error {
+	return g.reviewers(ctx, http.MethodPost, repo, number, logins)
+}
+
+func (g *GitHub) RemoveReviewers(ctx context.Context, repo string, number int, logins []string) error {
+	return g.reviewers(ctx, http.MethodDelete, repo, number, logins)
+}
+
+func (g *GitHub) reviewers(ctx context.Context, method string, repo string, number int, logins []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", g.baseURL, repo, number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package codehost
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitHubID(t *testing.T) {
+	repo, number, ok := ParseGitHubID("acme/backend-api#42")
+	assert.True(t, ok)
+	assert.Equal(t, "acme/backend-api", repo)
+	assert.Equal(t, 42, number)
+
+	for _, id := range []string{"pr-1001", "backend-api#42", "acme/backend-api#", "acme/backend-api#x", "payments/billing!7"} {
+		_, _, ok := ParseGitHubID(id)
+		assert.False(t, ok, id)
+	}
+}
+
+func TestGitHub_Reviewers(t *testing.T) {
+	type call struct {
+		method    string
+		path      string
+		auth      string
+		reviewers []string
+	}
+	calls := []call{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		calls = append(calls, call{r.Method, r.URL.Path, r.Header.Get("Authorization"), body.Reviewers})
+		if body.Reviewers[0] == "ghost" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Reviews may only be requested from collaborators."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewGitHub(server.URL+"/api/v3/", "t0ken")
+	ctx := context.Background()
+
+	require.NoError(t, client.RequestReviewers(ctx, "acme/backend-api", 42, []string{"octo-bob", "octo-carol"}))
+	require.NoError(t, client.RemoveReviewers(ctx, "acme/backend-api", 42, []string{"octo-bob"}))
+	err := client.RequestReviewers(ctx, "acme/backend-api", 42, []string{"ghost"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "422")
+	assert.Contains(t, err.Error(), "collaborators")
+
+	require.Len(t, calls, 3)
+	assert.Equal(t, call{http.MethodPost, "/api/v3/repos/acme/backend-api/pulls/42/requested_reviewers",
+		"Bearer t0ken", []string{"octo-bob", "octo-carol"}}, calls[0])
+	assert.Equal(t, http.MethodDelete, calls[1].method)
+	assert.Equal(t, []string{"octo-bob"}, calls[1].reviewers)
+}
@@ -0,0 +1,130 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule is a single CODEOWNERS line: a path pattern followed by its owners.
+type Rule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Line    int      `json:"line"`
+	re      *regexp.Regexp
+}
+
+type File struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Parse reads a CODEOWNERS file. Patterns follow the gitignore rules GitHub uses:
+// a leading or inner "/" anchors the pattern to the repository root, a trailing "/"
+// matches everything under a directory, "*" stays within a path segment, so "docs/*"
+// leaves out the subdirectories of docs, and "**" crosses segments.
+func Parse(data string) (*File, error) {
+	file := &File{Rules: []*Rule{}}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		// Inline comments.
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = strings.TrimSpace(text[:i])
+		}
+
+		fields := strings.Fields(text)
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", line)
+		}
+		if strings.Contains(pattern, "[") {
+			return nil, fmt.Errorf("line %d: character ranges are not supported", line)
+		}
+
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		file.Rules = append(file.Rules, &Rule{
+			Pattern: pattern,
+			Owners:  fields[1:],
+			Line:    line,
+			re:      re,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Match returns the last rule matching the path, as in GitHub, or nil.
+func (f *File) Match(path string) *Rule {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(path) {
+			return f.Rules[i]
+		}
+	}
+	return nil
+}
+
+// OwnerName turns "@alice" into "alice" and "@org/backend" into "backend".
+func OwnerName(owner string) string {
+	owner = strings.TrimPrefix(owner, "@")
+	if i := strings.LastIndex(owner, "/"); i >= 0 {
+		owner = owner[i+1:]
+	}
+	return owner
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	// "docs/*" owns the files right in docs, not those in its subdirectories.
+	filesOnly := strings.HasSuffix(pattern, "/*")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" || pattern == "*" || pattern == "**" {
+		return regexp.Compile(`^.*$`)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "/**/"):
+			b.WriteString("/(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**"):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	// A pattern naming a directory owns everything below it.
+	if !filesOnly {
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
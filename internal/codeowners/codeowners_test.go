@@ -0,0 +1,89 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `
+# Default owners
+*                   @backend
+
+*.js                @frontend
+/docs/              @alice @bob
+internal/**/sql     @dba  # inline comment
+migrations/         @dba
+README.md
+`
+
+func TestParse(t *testing.T) {
+	file, err := Parse(sample)
+
+	require.NoError(t, err)
+	require.Len(t, file.Rules, 6)
+	assert.Equal(t, "*", file.Rules[0].Pattern)
+	assert.Equal(t, 3, file.Rules[0].Line)
+	assert.Equal(t, []string{"@alice", "@bob"}, file.Rules[2].Owners)
+	assert.Equal(t, []string{"@dba"}, file.Rules[3].Owners)
+	assert.Empty(t, file.Rules[5].Owners)
+}
+
+func TestParse_Unsupported(t *testing.T) {
+	_, err := Parse("!vendor/ @nobody")
+	assert.Error(t, err)
+
+	_, err = Parse("*.[ch] @c")
+	assert.Error(t, err)
+}
+
+func TestFile_Match(t *testing.T) {
+	file, err := Parse(sample)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"cmd/main.go", "*"},
+		{"web/app/index.js", "*.js"},
+		{"docs/openapi.yml", "/docs/"},
+		{"docs/api/v1.md", "/docs/"},
+		{"internal/docs/notes.md", "*"},
+		{"internal/repository/sql/queries.sql", "internal/**/sql"},
+		{"internal/sql/x.sql", "internal/**/sql"},
+		{"migrations/001_teams_create.sql", "migrations/"},
+		{"/migrations/002.sql", "migrations/"},
+		{"README.md", "README.md"},
+		{"sub/README.md", "README.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule := file.Match(tt.path)
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.pattern, rule.Pattern)
+		})
+	}
+}
+
+func TestFile_MatchNone(t *testing.T) {
+	file, err := Parse("/docs/ @alice")
+	require.NoError(t, err)
+	assert.Nil(t, file.Match("cmd/main.go"))
+}
+
+func TestFile_MatchNested(t *testing.T) {
+	file, err := Parse("* @backend\ndocs/* @alice\napps/ @bob")
+	require.NoError(t, err)
+
+	assert.Equal(t, "docs/*", file.Match("docs/a.md").Pattern)
+	assert.Equal(t, "*", file.Match("docs/sub/b.md").Pattern)
+	assert.Equal(t, "apps/", file.Match("apps/web/src/main.go").Pattern)
+}
+
+func TestOwnerName(t *testing.T) {
+	assert.Equal(t, "alice", OwnerName("@alice"))
+	assert.Equal(t, "backend", OwnerName("@org/backend"))
+	assert.Equal(t, "u1", OwnerName("u1"))
+}
@@ -1,12 +1,179 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// ExclusionNotTogether keeps members from reviewing the same pull request.
+	ExclusionNotTogether = "not_together"
+	// ExclusionNoCrossReview keeps members from reviewing each other's pull requests.
+	ExclusionNoCrossReview = "no_cross_review"
+)
+
+const (
+	// AssignmentCreated marks reviewers assigned when the pull request was created.
+	AssignmentCreated = "create"
+	// AssignmentReassigned marks reviewers that replaced another one.
+	AssignmentReassigned = "reassign"
+)
+
+// Rules an assigned reviewer was picked by.
+const (
+	RuleReviewerGroup   = "reviewer_group"
+	RuleCodeOwners      = "code_owners"
+	RuleRepositoryTeams = "repository_teams"
+	RuleAuthorTeam      = "author_team"
+)
+
+// Verdicts reviewers give on the code host.
+const (
+	VerdictApproved         = "approved"
+	VerdictChangesRequested = "changes_requested"
+	VerdictDismissed        = "dismissed"
+)
+
+// Reviewer changes pushed to the code host.
+const (
+	CodeHostRequest = "request"
+	CodeHostRemove  = "remove"
+)
+
+const (
+	JobPending = "pending"
+	JobDone    = "done"
+	// JobFailed is a job that ran out of attempts.
+	JobFailed = "failed"
+)
+
+// Events sent to webhook subscriptions.
+const (
+	EventPRCreated          = "pr.created"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventPRMerged           = "pr.merged"
+	EventUserDeactivated    = "user.deactivated"
+	EventReviewSubmitted    = "review.submitted"
+)
+
+// Further events of the outbox; webhook subscriptions can't ask for them.
+const (
+	EventReviewerRemoved     = "reviewer.removed"
+	EventPRClosed            = "pr.closed"
+	EventPRReopened          = "pr.reopened"
+	EventUserActivityChanged = "user.activity_changed"
+	EventTeamChanged         = "team.changed"
+)
+
+// WebhookEvents are the events a subscription may ask for.
+var WebhookEvents = []string{EventPRCreated, EventReviewerAssigned, EventReviewerReassigned,
+	EventPRMerged, EventUserDeactivated, EventReviewSubmitted}
+
+// Notifications a user can get about their reviews.
+const (
+	NotifyAssigned = "assigned"
+	// NotifyReassignedAway is sent to the reviewer a reassignment took off the pull request.
+	NotifyReassignedAway = "reassigned_away"
+	NotifyMerged         = "merged"
+)
+
+// NotifyKinds are the notifications a user may ask for.
+var NotifyKinds = []string{NotifyAssigned, NotifyReassignedAway, NotifyMerged}
+
+// Channels notifications are sent through.
+const (
+	ChannelEmail = "email"
+	// ChannelTelegram is the chat the user linked with the bot.
+	ChannelTelegram = "telegram"
+)
+
+// NotifyChannels are the channels a user may choose.
+var NotifyChannels = []string{ChannelEmail, ChannelTelegram}
+
+// Reasons a preview gives for leaving someone out of the eligible pool.
+const (
+	SkipAuthor           = "author"
+	SkipRequiredReviewer = "required_reviewer"
+	SkipExclusionRule    = "exclusion_rule"
+	SkipInactive         = "inactive"
+	SkipAway             = "away"
+	// SkipNotOwner is for team members who own none of the changed files.
+	SkipNotOwner = "not_owner"
+)
+
+const (
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+)
 
 type PullRequest struct {
 	PullRequestShort
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt"`
-	MergedAt          *time.Time `json:"mergedAt"`
+	CoAuthorIDs       []string `json:"co_author_ids,omitempty"`
+	Repository        string   `json:"repository,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+	ChangedFiles      []string `json:"changed_files,omitempty"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	// RequiredReviewers are the assigned reviewers that came from a mandatory group.
+	RequiredReviewers []*RequiredReviewer     `json:"required_reviewers,omitempty"`
+	ReviewerHistory   []*ReviewerHistoryEntry `json:"reviewer_history,omitempty"`
+	CreatedAt         *time.Time              `json:"createdAt"`
+	MergedAt          *time.Time              `json:"mergedAt"`
+	// DueAt is when the review is expected, counted in working time of the author's team.
+	DueAt *time.Time `json:"dueAt,omitempty"`
+	// Assignment explains how the reviewers were picked; it is only set on the response
+	// of the request that picked them.
+	Assignment *AssignmentInfo `json:"assignment,omitempty"`
+}
+
+type AssignmentInfo struct {
+	Ownership []*OwnershipMatch `json:"ownership,omitempty"`
+	// FallbackToTeam is set when no owner of the changed files could review
+	// and the repository's teams (or the author's team) were used instead.
+	FallbackToTeam bool `json:"fallback_to_team,omitempty"`
+	// Excluded are the candidates exclusion rules removed.
+	Excluded []*ExcludedCandidate `json:"excluded,omitempty"`
+	// AllExcluded is set when exclusion rules removed every candidate.
+	AllExcluded bool `json:"all_candidates_excluded,omitempty"`
+}
+
+type ExcludedCandidate struct {
+	UserID string `json:"user_id"`
+	RuleID int64  `json:"rule_id"`
+}
+
+type ExclusionRule struct {
+	ID      int64    `json:"rule_id"`
+	Kind    string   `json:"kind"`
+	Members []string `json:"members"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// OwnershipMatch is the CODEOWNERS rule that matched a changed file.
+type OwnershipMatch struct {
+	Path   string   `json:"path"`
+	Rule   string   `json:"rule"`
+	Line   int      `json:"line"`
+	Owners []string `json:"owners"`
+}
+
+type RequiredReviewer struct {
+	UserID string `json:"user_id"`
+	Group  string `json:"group_name"`
+}
+
+// ReviewerGroup is a set of mandatory reviewers: Count of its members are added to every
+// pull request of the team or repository carrying one of the labels, on top of the
+// regular reviewers. Empty TeamName, Repository or Labels match anything.
+type ReviewerGroup struct {
+	Name       string   `json:"group_name"`
+	TeamName   string   `json:"team_name,omitempty"`
+	Repository string   `json:"repository,omitempty"`
+	Labels     []string `json:"labels"`
+	// Members are user ids, usernames or team names.
+	Members []string `json:"members"`
+	Count   int      `json:"count"`
 }
 
 type PullRequestShort struct {
@@ -16,13 +183,183 @@ type PullRequestShort struct {
 	Status   string `json:"status"`
 }
 
+// NewPullRequest is the input of pull request creation.
+type NewPullRequest struct {
+	PullRequestShort
+	CoAuthorIDs  []string `json:"co_author_ids"`
+	Repository   string   `json:"repository"`
+	Labels       []string `json:"labels"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// Repository groups pull requests: its teams review them and its policy says how.
+type Repository struct {
+	Name   string           `json:"repository_name"`
+	Teams  []string         `json:"teams"`
+	Policy RepositoryPolicy `json:"policy"`
+}
+
+type RepositoryPolicy struct {
+	// ReviewerCount is how many reviewers a new pull request gets; zero keeps the default.
+	ReviewerCount int `json:"reviewer_count"`
+	// Strategy overrides the service-wide assignment strategy when set.
+	Strategy          string `json:"strategy,omitempty"`
+	RequiredApprovals int    `json:"required_approvals"`
+}
+
+// AssignmentPreview is what assigning reviewers to a pull request would do, without doing it.
+type AssignmentPreview struct {
+	Strategy          string              `json:"strategy"`
+	Rule              string              `json:"rule"`
+	RequiredReviewers []*RequiredReviewer `json:"required_reviewers"`
+	Reviewers         []string            `json:"reviewers"`
+	// Pool are the regular candidates the strategy picked from.
+	Pool       []string            `json:"eligible_pool"`
+	Skipped    []*SkippedCandidate `json:"skipped"`
+	Assignment *AssignmentInfo     `json:"assignment,omitempty"`
+}
+
+type SkippedCandidate struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+	RuleID int64  `json:"rule_id,omitempty"`
+}
+
+// ReviewerHistoryEntry is one stay of a reviewer on a pull request; UnassignedAt is nil
+// while they are still on it. Verdict is their latest review.
+type ReviewerHistoryEntry struct {
+	UserID       string     `json:"user_id"`
+	Group        string     `json:"group_name,omitempty"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty"`
+	ReplacedBy   string     `json:"replaced_by,omitempty"`
+	// Assigned is false for people who reviewed without being assigned.
+	Assigned   bool       `json:"assigned"`
+	Verdict    string     `json:"verdict,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// AssignmentRecord explains how a reviewer got on a pull request.
+type AssignmentRecord struct {
+	ID            int64  `json:"record_id"`
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Action        string `json:"action"`
+	Strategy      string `json:"strategy"`
+	Rule          string `json:"rule"`
+	// RuleDetail is the reviewer group or the ownership patterns behind the rule.
+	RuleDetail string `json:"rule_detail,omitempty"`
+	// Fallback is set when no owner of the changed files could review.
+	Fallback bool `json:"fallback"`
+	// Actor is who asked for a reassignment; empty for automatic ones.
+	Actor          string    `json:"actor,omitempty"`
+	ReplacedUserID string    `json:"replaced_user_id,omitempty"`
+	AssignedAt     time.Time `json:"assigned_at"`
+}
+
+// CodeHostJob is a reviewer change waiting to be pushed to the code host.
+type CodeHostJob struct {
+	ID            int64     `json:"job_id"`
+	PullRequestID string    `json:"pull_request_id"`
+	Action        string    `json:"action"`
+	Reviewers     []string  `json:"reviewers"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+// WebhookSubscription asks for events to be posted to URL. Secret signs the deliveries
+// and is never given back.
+type WebhookSubscription struct {
+	ID        int64     `json:"subscription_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one event on its way to a subscription; it uses the job statuses.
+type WebhookDelivery struct {
+	ID             int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	// ResponseStatus is the HTTP status of the last attempt, 0 if there was no response.
+	ResponseStatus int       `json:"response_status,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// EventPayload is the body posted to subscribers.
+type EventPayload struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// OutboxMessage is a domain event stored with the change it describes, waiting to be
+// published. AggregateID is the pull request, user or team the event is about.
+type OutboxMessage struct {
+	ID          int64           `json:"id"`
+	EventType   string          `json:"event"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PublishedAt *time.Time      `json:"-"`
+	Attempts    int             `json:"-"`
+	LastError   string          `json:"-"`
+}
+
+// ReviewerEvent is the data of the reviewer events; ReplacedUserID is the reviewer taken
+// off by a reassignment.
+type ReviewerEvent struct {
+	PullRequestID  string `json:"pull_request_id"`
+	UserID         string `json:"user_id"`
+	ReplacedUserID string `json:"replaced_user_id,omitempty"`
+	Actor          string `json:"actor,omitempty"`
+}
+
+// ReviewEvent is the data of review.submitted: the verdict a reviewer gave on the code host.
+type ReviewEvent struct {
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	Verdict       string    `json:"verdict"`
+	ReviewedAt    time.Time `json:"reviewed_at"`
+}
+
+// NotificationPreferences are the notifications a user gets and the channels they go to.
+type NotificationPreferences struct {
+	UserID   string   `json:"user_id"`
+	Channels []string `json:"channels"`
+	Events   []string `json:"events"`
+}
+
+// Pairing counts the reviews a reviewer did on pull requests of an author.
+type Pairing struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Reviews    int    `json:"reviews"`
+}
+
 type Team struct {
-	Name    string    `json:"team_name"`
-	Members []*Member `json:"members"`
+	Name    string      `json:"team_name"`
+	Members []*Member   `json:"members"`
+	Policy  *TeamPolicy `json:"policy,omitempty"`
+}
+
+// TeamPolicy is the seniority mix the team wants on the reviews of its pull requests.
+type TeamPolicy struct {
+	RequireSenior bool `json:"require_senior"`
+	NoTwoJuniors  bool `json:"no_two_juniors"`
 }
 type User struct {
 	Member
 	TeamName string `json:"team_name"`
+	Profile
 }
 
 type Member struct {
@@ -30,3 +367,48 @@ type Member struct {
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
+
+type Profile struct {
+	Email          string   `json:"email,omitempty"`
+	SlackHandle    string   `json:"slack_handle,omitempty"`
+	TelegramHandle string   `json:"telegram_handle,omitempty"`
+	TimeZone       string   `json:"time_zone,omitempty"`
+	Seniority      string   `json:"seniority,omitempty"`
+	Skills         []string `json:"skills,omitempty"`
+	// WorkStart and WorkEnd are local "HH:MM" times in TimeZone, WorkDays are weekdays (0 is Sunday).
+	WorkStart string  `json:"work_start,omitempty"`
+	WorkEnd   string  `json:"work_end,omitempty"`
+	WorkDays  []int64 `json:"work_days,omitempty"`
+}
+
+// ProfileUpdate is a partial update of a user profile: nil fields are left unchanged.
+type ProfileUpdate struct {
+	UserID         string    `json:"user_id"`
+	Email          *string   `json:"email"`
+	SlackHandle    *string   `json:"slack_handle"`
+	TelegramHandle *string   `json:"telegram_handle"`
+	TimeZone       *string   `json:"time_zone"`
+	Seniority      *string   `json:"seniority"`
+	Skills         *[]string `json:"skills"`
+	WorkStart      *string   `json:"work_start"`
+	WorkEnd        *string   `json:"work_end"`
+	WorkDays       *[]int64  `json:"work_days"`
+}
+
+type AwayPeriod struct {
+	ID       int64     `json:"away_id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+	// Deactivated is set when the scheduler switched the user off at the window start,
+	// so that only those users are switched back on at the end.
+	Deactivated bool `json:"-"`
+}
+
+type Holiday struct {
+	TeamName string `json:"team_name"`
+	// Date is a local calendar date in "2006-01-02" format.
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
@@ -17,6 +17,11 @@ var (
 		Message: "team_name already exists",
 	}
 
+	ErrRepositoryExists = APIError{
+		Code:    "REPOSITORY_EXISTS",
+		Message: "repository_name already exists",
+	}
+
 	ErrPRExists = APIError{
 		Code:    "PR_EXISTS",
 		Message: "PR id already exists",
@@ -27,6 +32,76 @@ var (
 		Message: "cannot reassign on merged PR",
 	}
 
+	ErrPRClosed = APIError{
+		Code:    "PR_CLOSED",
+		Message: "cannot reassign on closed PR",
+	}
+
+	ErrNotAssigned = APIError{
+		Code:    "NOT_ASSIGNED",
+		Message: "reviewer is not assigned to this PR",
+	}
+
+	ErrNotApproved = APIError{
+		Code:    "NOT_APPROVED",
+		Message: "PR doesn't have the required approvals",
+	}
+
+	ErrInvalidProfile = APIError{
+		Code:    "INVALID_PROFILE",
+		Message: "invalid user profile",
+	}
+
+	ErrInvalidCodeOwners = APIError{
+		Code:    "INVALID_CODEOWNERS",
+		Message: "invalid code owners file",
+	}
+
+	ErrInvalidPolicy = APIError{
+		Code:    "INVALID_POLICY",
+		Message: "invalid repository policy",
+	}
+
+	ErrInvalidReviewerGroup = APIError{
+		Code:    "INVALID_REVIEWER_GROUP",
+		Message: "invalid reviewer group",
+	}
+
+	ErrInvalidExclusionRule = APIError{
+		Code:    "INVALID_EXCLUSION_RULE",
+		Message: "invalid exclusion rule",
+	}
+
+	ErrInvalidSignature = APIError{
+		Code:    "INVALID_SIGNATURE",
+		Message: "webhook signature doesn't match",
+	}
+
+	ErrInvalidWebhook = APIError{
+		Code:    "INVALID_WEBHOOK",
+		Message: "invalid webhook payload",
+	}
+
+	ErrInvalidSubscription = APIError{
+		Code:    "INVALID_SUBSCRIPTION",
+		Message: "invalid webhook subscription",
+	}
+
+	ErrDeliveryNotFailed = APIError{
+		Code:    "DELIVERY_NOT_FAILED",
+		Message: "only failed deliveries can be replayed",
+	}
+
+	ErrInvalidPreferences = APIError{
+		Code:    "INVALID_PREFERENCES",
+		Message: "invalid notification preferences",
+	}
+
+	ErrOutboundDisabled = APIError{
+		Code:    "OUTBOUND_DISABLED",
+		Message: "outbound webhooks are disabled",
+	}
+
 	ErrNotFound = APIError{
 		Code:    "NOT_FOUND",
 		Message: "resource not found",
@@ -0,0 +1,71 @@
+// Package events is the in-process bus the service announces its changes on.
+package events
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Bus delivers events to the handlers subscribed to their type, synchronously and in
+// the order they subscribed. The zero value is not usable, use New.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]func(context.Context, interface{}) error
+	all      []func(context.Context, interface{}) error
+}
+
+func New() *Bus {
+	return &Bus{handlers: map[reflect.Type][]func(context.Context, interface{}) error{}}
+}
+
+// Subscribe calls h with every published event of type E.
+func Subscribe[E any](b *Bus, h func(ctx context.Context, e E) error) {
+	t := reflect.TypeOf((*E)(nil)).Elem()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[t] = append(b.handlers[t], func(ctx context.Context, e interface{}) error {
+		return h(ctx, e.(E))
+	})
+}
+
+// SubscribeAll calls h with every published event, after the handlers of its type.
+func (b *Bus) SubscribeAll(h func(ctx context.Context, e interface{}) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, h)
+}
+
+// Publish hands the event to its handlers and stops at the first one that fails.
+// The service publishes inside the transaction of the change, so an error undoes it.
+func (b *Bus) Publish(ctx context.Context, e interface{}) error {
+	b.mu.RLock()
+	handlers := append(append([]func(context.Context, interface{}) error{}, b.handlers[reflect.TypeOf(e)]...), b.all...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			return fmt.Errorf("%T handler: %w", e, err)
+		}
+	}
+	return nil
+}
+
+type afterCommitKey struct{}
+
+// WithAfterCommit returns a context whose AfterCommit calls go to register.
+func WithAfterCommit(ctx context.Context, register func(f func())) context.Context {
+	return context.WithValue(ctx, afterCommitKey{}, register)
+}
+
+// AfterCommit runs f once the change the event is about is committed, or right away
+// when there is nothing to wait for. Handlers talking to the outside world use it so they
+// neither act on a change that is rolled back nor hold the transaction open.
+func AfterCommit(ctx context.Context, f func()) {
+	if register, ok := ctx.Value(afterCommitKey{}).(func(f func())); ok {
+		register(f)
+		return
+	}
+	f()
+}
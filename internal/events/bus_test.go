@@ -0,0 +1,78 @@
+package events
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := New()
+	calls := []string{}
+	Subscribe(bus, func(ctx context.Context, e PRMerged) error {
+		calls = append(calls, "merged:"+e.PR.ID)
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, e PRMerged) error {
+		calls = append(calls, "merged again")
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, e *PRMerged) error {
+		calls = append(calls, "pointer")
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, e TeamChanged) error {
+		calls = append(calls, "team:"+e.Team.Name)
+		return nil
+	})
+	bus.SubscribeAll(func(ctx context.Context, e interface{}) error {
+		_, ok := e.(PRMerged)
+		calls = append(calls, "all")
+		assert.True(t, ok)
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), PRMerged{PR: &domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr-1"}}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"merged:pr-1", "merged again", "all"}, calls)
+}
+
+func TestBus_PublishStopsAtError(t *testing.T) {
+	bus := New()
+	failure := errors.New("outbox is down")
+	called := false
+	Subscribe(bus, func(ctx context.Context, e PRClosed) error {
+		return failure
+	})
+	Subscribe(bus, func(ctx context.Context, e PRClosed) error {
+		called = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), PRClosed{})
+
+	assert.ErrorIs(t, err, failure)
+	assert.Contains(t, err.Error(), "events.PRClosed")
+	assert.False(t, called)
+	assert.NoError(t, bus.Publish(context.Background(), PRReopened{}))
+}
+
+func TestAfterCommit(t *testing.T) {
+	ran := false
+	AfterCommit(context.Background(), func() { ran = true })
+	assert.True(t, ran)
+
+	pending := []func(){}
+	ctx := WithAfterCommit(context.Background(), func(f func()) { pending = append(pending, f) })
+	ran = false
+	AfterCommit(ctx, func() { ran = true })
+	assert.False(t, ran)
+	require.Len(t, pending, 1)
+	pending[0]()
+	assert.True(t, ran)
+}
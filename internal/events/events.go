@@ -0,0 +1,60 @@
+package events
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"time"
+)
+
+// PRCreated is published once a pull request is stored with all its reviewers.
+type PRCreated struct {
+	PR *domain.PullRequest
+}
+
+// ReviewerAssigned is published for every reviewer a new pull request gets.
+type ReviewerAssigned struct {
+	PullRequestID string
+	UserID        string
+	// Group is the mandatory reviewer group the reviewer comes from, if any.
+	Group string
+}
+
+// ReviewerReassigned is published when OldUserID is replaced by NewUserID.
+type ReviewerReassigned struct {
+	PullRequestID string
+	OldUserID     string
+	NewUserID     string
+	// Actor is who asked for it; empty for automatic reassignments.
+	Actor string
+}
+
+// ReviewSubmitted is published when the verdict a reviewer gave on the code host is
+// recorded.
+type ReviewSubmitted struct {
+	PullRequestID string
+	UserID        string
+	Verdict       string
+	At            time.Time
+}
+
+type PRMerged struct {
+	PR *domain.PullRequest
+}
+
+type PRClosed struct {
+	PR *domain.PullRequest
+}
+
+type PRReopened struct {
+	PR *domain.PullRequest
+}
+
+// UserActivityChanged is published when a user is switched on or off.
+type UserActivityChanged struct {
+	User      *domain.User
+	WasActive bool
+}
+
+// TeamChanged is published when a team is created or its policy changes.
+type TeamChanged struct {
+	Team *domain.Team
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UploadCodeOwners takes a CODEOWNERS file as the raw request body.
+func (h *Handler) UploadCodeOwners(c echo.Context) error {
+	repository := c.QueryParam("repository")
+	if repository == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.log.Debugf("failed to read body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid body",
+			},
+		})
+	}
+
+	file, err := h.s.UploadCodeOwners(repository, string(body))
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok && apiErr.Code == errors.ErrInvalidCodeOwners.Code {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": apiErr,
+			})
+		}
+		h.log.Debugf("failed to upload code owners: %v", err)
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"repository": repository,
+		"rules":      file.Rules,
+	})
+}
+
+func (h *Handler) GetCodeOwners(c echo.Context) error {
+	repository := c.QueryParam("repository")
+	if repository == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	content, file, err := h.s.GetCodeOwners(repository)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("code owners of %s not found", repository)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get code owners: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"repository": repository,
+		"content":    content,
+		"rules":      file.Rules,
+	})
+}
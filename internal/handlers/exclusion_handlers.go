@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) AddExclusionRule(c echo.Context) error {
+	var rule domain.ExclusionRule
+	err := c.Bind(&rule)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	newRule, err := h.s.CreateExclusionRule(&rule)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidExclusionRule:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrInvalidExclusionRule,
+			})
+		case errors.ErrNotFound:
+			h.log.Debug("member of exclusion rule not found")
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to create exclusion rule: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newRule)
+}
+
+func (h *Handler) GetExclusionRules(c echo.Context) error {
+	rules, err := h.s.GetExclusionRules()
+	if err != nil {
+		h.log.Debugf("failed to get exclusion rules: %v", err)
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"rules": rules,
+	})
+}
+
+func (h *Handler) DeleteExclusionRule(c echo.Context) error {
+	id, err := strconv.ParseInt(c.QueryParam("rule_id"), 10, 64)
+	if err != nil {
+		h.log.Debugf("not correct rule id: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct rule id",
+			},
+		})
+	}
+
+	err = h.s.DeleteExclusionRule(id)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("exclusion rule with id: %d not found", id)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to delete exclusion rule: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
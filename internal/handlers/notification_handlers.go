@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) GetNotificationPreferences(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		h.log.Debugf("not correct user id")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct user id",
+			},
+		})
+	}
+
+	prefs, err := h.s.GetNotificationPreferences(userID)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", userID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get notification preferences: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, prefs)
+}
+
+func (h *Handler) SetNotificationPreferences(c echo.Context) error {
+	var prefs domain.NotificationPreferences
+	err := c.Bind(&prefs)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	saved, err := h.s.SetNotificationPreferences(&prefs)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok && apiErr.Code == errors.ErrInvalidPreferences.Code {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": apiErr,
+			})
+		}
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", prefs.UserID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to save notification preferences: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, saved)
+}
@@ -5,7 +5,6 @@ import (
 	"Pull-Requests-master/internal/errors"
 	"Pull-Requests-master/internal/service"
 	"Pull-Requests-master/package/logger"
-	"database/sql"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -16,15 +15,15 @@ type Handler struct {
 	log *logger.Logger
 }
 
-func NewHandler(db *sql.DB, log *logger.Logger) *Handler {
+func NewHandler(s *service.Service, log *logger.Logger) *Handler {
 	return &Handler{
-		s:   service.NewService(db, log),
+		s:   s,
 		log: log,
 	}
 }
 
 func (h *Handler) CreatePR(c echo.Context) error {
-	var pr domain.PullRequestShort
+	var pr domain.NewPullRequest
 	err := c.Bind(&pr)
 	if err != nil {
 		h.log.Debugf("failed to pars json: %v", err)
@@ -50,7 +49,7 @@ func (h *Handler) CreatePR(c echo.Context) error {
 	if err != nil {
 		switch err {
 		case errors.ErrNotFound:
-			h.log.Debugf(err.Error())
+			h.log.Debug(err.Error())
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
 				"error": errors.ErrNotFound,
 			})
@@ -70,6 +69,49 @@ func (h *Handler) CreatePR(c echo.Context) error {
 	})
 }
 
+// PreviewAssignment takes a pull request that doesn't exist yet; only the author is required.
+func (h *Handler) PreviewAssignment(c echo.Context) error {
+	var pr domain.NewPullRequest
+	err := c.Bind(&pr)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if pr.AuthorID == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	preview, err := h.s.PreviewAssignment(&pr)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debug(err.Error())
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to preview assignment: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"preview": preview,
+	})
+}
+
 func (h *Handler) MergePR(c echo.Context) error {
 	var req struct {
 		PRID string `json:"pull_request_id"`
@@ -99,10 +141,15 @@ func (h *Handler) MergePR(c echo.Context) error {
 	if err != nil {
 		switch err {
 		case errors.ErrNotFound:
-			h.log.Debugf(err.Error())
+			h.log.Debug(err.Error())
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
 				"error": errors.ErrNotFound,
 			})
+		case errors.ErrNotApproved:
+			h.log.Debugf("PR with id: %s not approved", req.PRID)
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": errors.ErrNotApproved,
+			})
 		default:
 			h.log.Debugf("failed to merge PR: %v", err)
 			return c.JSON(http.StatusInternalServerError, err)
@@ -118,6 +165,7 @@ func (h *Handler) ReassignReviewersPR(c echo.Context) error {
 	var req struct {
 		PRID   string `json:"pull_request_id"`
 		OldRev string `json:"old_reviewer_id"`
+		Actor  string `json:"actor"`
 	}
 	err := c.Bind(&req)
 	if err != nil {
@@ -140,11 +188,11 @@ func (h *Handler) ReassignReviewersPR(c echo.Context) error {
 		})
 	}
 
-	pr, err := h.s.ReassignReviewersPR(req.PRID, req.OldRev)
+	pr, replacedBy, err := h.s.ReassignReviewersPR(req.PRID, req.OldRev, req.Actor)
 	if err != nil {
 		switch err {
 		case errors.ErrNotFound:
-			h.log.Debugf(err.Error())
+			h.log.Debug(err.Error())
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
 				"error": errors.ErrNotFound,
 			})
@@ -153,6 +201,16 @@ func (h *Handler) ReassignReviewersPR(c echo.Context) error {
 			return c.JSON(http.StatusConflict, map[string]interface{}{
 				"error": errors.ErrPRMerged,
 			})
+		case errors.ErrPRClosed:
+			h.log.Debugf("PR with id: %s closed", req.PRID)
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": errors.ErrPRClosed,
+			})
+		case errors.ErrNotAssigned:
+			h.log.Debugf("user %s is not a reviewer of PR %s", req.OldRev, req.PRID)
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": errors.ErrNotAssigned,
+			})
 		default:
 			h.log.Debugf("failed to create PR: %v", err)
 			return c.JSON(http.StatusInternalServerError, err)
@@ -161,6 +219,38 @@ func (h *Handler) ReassignReviewersPR(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"pr":          pr,
-		"replaced_by": req.OldRev,
+		"replaced_by": replacedBy,
+	})
+}
+
+func (h *Handler) GetAssignments(c echo.Context) error {
+	prID := c.QueryParam("pull_request_id")
+	if prID == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	records, err := h.s.GetAssignments(prID, c.QueryParam("user_id"))
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debug(err.Error())
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get assignments: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pull_request_id": prID,
+		"assignments":     records,
 	})
 }
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) AddRepository(c echo.Context) error {
+	var repo domain.Repository
+	err := c.Bind(&repo)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if repo.Name == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	newRepo, err := h.s.CreateRepository(&repo)
+	if err != nil {
+		switch err {
+		case errors.ErrRepositoryExists:
+			h.log.Debugf("repository with name: %s already exist", repo.Name)
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrRepositoryExists,
+			})
+		case errors.ErrInvalidPolicy:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrInvalidPolicy,
+			})
+		case errors.ErrNotFound:
+			h.log.Debugf("team of repository %s not found", repo.Name)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to create repository: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newRepo)
+}
+
+// UpdateRepository replaces the teams and the policy of a repository.
+func (h *Handler) UpdateRepository(c echo.Context) error {
+	var repo domain.Repository
+	err := c.Bind(&repo)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if repo.Name == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	newRepo, err := h.s.UpdateRepository(&repo)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidPolicy:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrInvalidPolicy,
+			})
+		case errors.ErrNotFound:
+			h.log.Debugf("repository %s or its team not found", repo.Name)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to update repository: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, newRepo)
+}
+
+func (h *Handler) GetRepository(c echo.Context) error {
+	name := c.QueryParam("repository_name")
+	if name == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	repo, err := h.s.GetRepository(name)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("repository with name: %s doesn't found", name)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get repository: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, repo)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SaveReviewerGroup creates a mandatory reviewer group or replaces the one with the same name.
+func (h *Handler) SaveReviewerGroup(c echo.Context) error {
+	var group domain.ReviewerGroup
+	err := c.Bind(&group)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if group.Name == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	newGroup, err := h.s.SaveReviewerGroup(&group)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidReviewerGroup:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrInvalidReviewerGroup,
+			})
+		case errors.ErrNotFound:
+			h.log.Debugf("team with name: %s not found", group.TeamName)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to save reviewer group: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, newGroup)
+}
+
+func (h *Handler) GetReviewerGroups(c echo.Context) error {
+	groups, err := h.s.GetReviewerGroups()
+	if err != nil {
+		h.log.Debugf("failed to get reviewer groups: %v", err)
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"groups": groups,
+	})
+}
+
+func (h *Handler) DeleteReviewerGroup(c echo.Context) error {
+	name := c.QueryParam("group_name")
+	if name == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	err := h.s.DeleteReviewerGroup(name)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("reviewer group %s not found", name)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to delete reviewer group: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
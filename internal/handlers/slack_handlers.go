@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/slack"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SlackCommand takes the /review slash command; the body is read raw since the signature
+// covers it, and the reply is shown to the user who ran the command.
+func (h *Handler) SlackCommand(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.log.Debugf("failed to read body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid body",
+			},
+		})
+	}
+
+	reply, err := h.s.HandleSlackCommand(c.Request().Header.Get(slack.HeaderTimestamp),
+		c.Request().Header.Get(slack.HeaderSignature), body)
+	if err != nil {
+		return h.webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reply)
+}
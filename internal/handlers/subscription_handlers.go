@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) AddSubscription(c echo.Context) error {
+	var sub domain.WebhookSubscription
+	err := c.Bind(&sub)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	newSub, err := h.s.CreateSubscription(&sub)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok && apiErr.Code == errors.ErrInvalidSubscription.Code {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": apiErr,
+			})
+		}
+		h.log.Debugf("failed to create subscription: %v", err)
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, newSub)
+}
+
+func (h *Handler) GetSubscriptions(c echo.Context) error {
+	subs, err := h.s.GetSubscriptions()
+	if err != nil {
+		h.log.Debugf("failed to get subscriptions: %v", err)
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+func (h *Handler) DeleteSubscription(c echo.Context) error {
+	id, err := strconv.ParseInt(c.QueryParam("subscription_id"), 10, 64)
+	if err != nil {
+		h.log.Debugf("not correct subscription id: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct subscription id",
+			},
+		})
+	}
+
+	err = h.s.DeleteSubscription(id)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("subscription with id: %d not found", id)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to delete subscription: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetDeliveries(c echo.Context) error {
+	id, err := strconv.ParseInt(c.QueryParam("subscription_id"), 10, 64)
+	if err != nil {
+		h.log.Debugf("not correct subscription id: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct subscription id",
+			},
+		})
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "", domain.JobPending, domain.JobDone, domain.JobFailed:
+	default:
+		h.log.Debugf("not correct delivery status: %s", status)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct delivery status",
+			},
+		})
+	}
+
+	deliveries, err := h.s.GetDeliveries(id, status)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("subscription with id: %d not found", id)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get deliveries: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+	})
+}
+
+func (h *Handler) ReplayDelivery(c echo.Context) error {
+	var req struct {
+		DeliveryID int64 `json:"delivery_id"`
+	}
+	err := c.Bind(&req)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if req.DeliveryID <= 0 {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	delivery, err := h.s.ReplayDelivery(req.DeliveryID)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("delivery with id: %d not found", req.DeliveryID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		case errors.ErrDeliveryNotFailed:
+			h.log.Debugf("delivery with id: %d not failed", req.DeliveryID)
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": errors.ErrDeliveryNotFailed,
+			})
+		case errors.ErrOutboundDisabled:
+			h.log.Debug("outbound webhooks are disabled")
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error": errors.ErrOutboundDisabled,
+			})
+		default:
+			h.log.Debugf("failed to replay delivery: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"delivery": delivery,
+	})
+}
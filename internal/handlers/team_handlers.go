@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"Pull-Requests-master/internal/calendar"
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/errors"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -77,3 +81,202 @@ func (h *Handler) GetTeam(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, team)
 }
+
+// SetTeamPolicy sets the seniority mix of reviewers for pull requests of the team.
+func (h *Handler) SetTeamPolicy(c echo.Context) error {
+	teamName := c.QueryParam("team_name")
+	if teamName == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	var policy domain.TeamPolicy
+	err := c.Bind(&policy)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	newPolicy, err := h.s.SetTeamPolicy(teamName, &policy)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("team with name: %s doesn't found", teamName)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to set team policy: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"team_name": teamName,
+		"policy":    newPolicy,
+	})
+}
+
+// ImportHolidays accepts an iCalendar file (Content-Type text/calendar or format=ical)
+// or a YAML list of {date, name} entries.
+func (h *Handler) ImportHolidays(c echo.Context) error {
+	teamName := c.QueryParam("team_name")
+	if teamName == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.log.Debugf("failed to read body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid body",
+			},
+		})
+	}
+
+	var holidays []*domain.Holiday
+	if c.QueryParam("format") == "ical" || strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/calendar") {
+		holidays, err = calendar.ParseICal(body)
+	} else {
+		holidays, err = calendar.ParseYAML(body)
+	}
+	if err != nil {
+		h.log.Debugf("failed to parse holidays: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": err.Error(),
+			},
+		})
+	}
+
+	imported, err := h.s.ImportHolidays(teamName, holidays)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("team with name: %s doesn't found", teamName)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to import holidays: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"team_name": teamName,
+		"holidays":  imported,
+	})
+}
+
+func (h *Handler) GetHolidays(c echo.Context) error {
+	teamName := c.QueryParam("team_name")
+	if teamName == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	from := c.QueryParam("from")
+	if from == "" {
+		from = time.Now().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", from); err != nil {
+		h.log.Debugf("invalid from date: %s", from)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid from date",
+			},
+		})
+	}
+
+	holidays, err := h.s.GetHolidays(teamName, from)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("team with name: %s doesn't found", teamName)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get holidays: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"team_name": teamName,
+		"holidays":  holidays,
+	})
+}
+
+// GetPairings returns the author-reviewer matrix of the team, optionally since a date.
+func (h *Handler) GetPairings(c echo.Context) error {
+	teamName := c.QueryParam("team_name")
+	if teamName == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	var since time.Time
+	if from := c.QueryParam("from"); from != "" {
+		var err error
+		since, err = time.Parse("2006-01-02", from)
+		if err != nil {
+			h.log.Debugf("invalid from date: %s", from)
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "BAD_REQUEST",
+					"message": "invalid from date",
+				},
+			})
+		}
+	}
+
+	pairings, err := h.s.GetPairings(teamName, since)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("team with name: %s doesn't found", teamName)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get pairings: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"team_name": teamName,
+		"pairings":  pairings,
+	})
+}
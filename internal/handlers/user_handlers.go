@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/errors"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -81,3 +83,177 @@ func (h *Handler) GetUserReview(c echo.Context) error {
 		"pull_requests": reviews,
 	})
 }
+
+func (h *Handler) GetUser(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		h.log.Debugf("not correct user id")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct user id",
+			},
+		})
+	}
+
+	user, err := h.s.GetUser(userID)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", userID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get user: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) UpdateUserProfile(c echo.Context) error {
+	var req domain.ProfileUpdate
+	err := c.Bind(&req)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if req.UserID == "" {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	user, err := h.s.UpdateUserProfile(&req)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", req.UserID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		case errors.ErrInvalidProfile:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": errors.ErrInvalidProfile,
+			})
+		default:
+			h.log.Debugf("failed to update user profile: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) AddAwayPeriod(c echo.Context) error {
+	var period domain.AwayPeriod
+	err := c.Bind(&period)
+	if err != nil {
+		h.log.Debugf("failed to pars json: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "Invalid JSON",
+			},
+		})
+	}
+
+	if period.UserID == "" || period.StartsAt.IsZero() || !period.EndsAt.After(period.StartsAt) {
+		h.log.Debug("invalid data")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid data",
+			},
+		})
+	}
+
+	newPeriod, err := h.s.AddAwayPeriod(&period)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", period.UserID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to add away period: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newPeriod)
+}
+
+func (h *Handler) GetAwayPeriods(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		h.log.Debugf("not correct user id")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct user id",
+			},
+		})
+	}
+
+	periods, err := h.s.GetAwayPeriods(userID)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("user with id: %s not found", userID)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to get away periods: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user_id":      userID,
+		"away_periods": periods,
+	})
+}
+
+func (h *Handler) DeleteAwayPeriod(c echo.Context) error {
+	id, err := strconv.ParseInt(c.QueryParam("away_id"), 10, 64)
+	if err != nil {
+		h.log.Debugf("not correct away id: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "not correct away id",
+			},
+		})
+	}
+
+	err = h.s.DeleteAwayPeriod(id)
+	if err != nil {
+		switch err {
+		case errors.ErrNotFound:
+			h.log.Debugf("away period with id: %d not found", id)
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		default:
+			h.log.Debugf("failed to delete away period: %v", err)
+			return c.JSON(http.StatusInternalServerError, err)
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"Pull-Requests-master/internal/errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GitHubWebhook takes GitHub deliveries; the body is read raw since the signature covers it.
+func (h *Handler) GitHubWebhook(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.log.Debugf("failed to read body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid body",
+			},
+		})
+	}
+
+	action, pr, err := h.s.HandleGitHubWebhook(c.Request().Header.Get("X-GitHub-Event"),
+		c.Request().Header.Get("X-Hub-Signature-256"), body)
+	if err != nil {
+		return h.webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"action": action,
+		"pr":     pr,
+	})
+}
+
+func (h *Handler) GitLabWebhook(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.log.Debugf("failed to read body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "BAD_REQUEST",
+				"message": "invalid body",
+			},
+		})
+	}
+
+	action, pr, err := h.s.HandleGitLabWebhook(c.Request().Header.Get("X-Gitlab-Event"),
+		c.Request().Header.Get("X-Gitlab-Token"), body)
+	if err != nil {
+		return h.webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"action": action,
+		"pr":     pr,
+	})
+}
+
+func (h *Handler) webhookError(c echo.Context, err error) error {
+	if apiErr, ok := err.(errors.APIError); ok {
+		switch apiErr.Code {
+		case errors.ErrInvalidSignature.Code:
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"error": apiErr,
+			})
+		case errors.ErrInvalidWebhook.Code:
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": apiErr,
+			})
+		case errors.ErrNotFound.Code:
+			h.log.Debug(err.Error())
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error": errors.ErrNotFound,
+			})
+		}
+	}
+	h.log.Debugf("failed to handle webhook: %v", err)
+	return c.JSON(http.StatusInternalServerError, err)
+}
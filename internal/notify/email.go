@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// EmailConfig is the SMTP server mail is submitted to. Username and Password are only
+// used when Username is set.
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// StartTLS upgrades the connection before authenticating.
+	StartTLS bool
+	Timeout  time.Duration
+}
+
+// Email sends multipart/alternative mail with the plain-text and HTML bodies.
+type Email struct {
+	cfg EmailConfig
+}
+
+func NewEmail(cfg EmailConfig) *Email {
+	if cfg.Port == 0 {
+		cfg.Port = 25
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &Email{cfg: cfg}
+}
+
+func (e *Email) Send(ctx context.Context, user *domain.User, msg *Message) error {
+	if user.Email == "" {
+		return ErrNoAddress
+	}
+	body, err := e.compose(user.Email, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	dialer := net.Dialer{Timeout: e.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(e.cfg.Timeout))
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if e.cfg.StartTLS {
+		if err := c.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return fmt.Errorf("starttls %s: %w", addr, err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("auth %s: %w", addr, err)
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(user.Email); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (e *Email) compose(to string, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
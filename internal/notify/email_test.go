@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpServer accepts one session and records the envelope and the message.
+type smtpServer struct {
+	ln   net.Listener
+	from string
+	rcpt []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpServer{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.Fields(line + " ")[0])
+		switch verb {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-fake")
+			tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			s.from = address(strings.TrimPrefix(line, "MAIL FROM:"))
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, address(strings.TrimPrefix(line, "RCPT TO:")))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(data)
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// address drops the angle brackets and the parameters of a MAIL or RCPT path.
+func address(path string) string {
+	path = strings.TrimPrefix(path, "<")
+	if i := strings.Index(path, ">"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+func TestEmail_Send(t *testing.T) {
+	server := newSMTPServer(t)
+	email := NewEmail(EmailConfig{Host: "127.0.0.1", Port: server.port(), From: "reviews@example.com", Timeout: time.Second})
+
+	user := &domain.User{Member: domain.Member{ID: "u2", Username: "Bob"}, Profile: domain.Profile{Email: "bob@example.com"}}
+	msg, err := Render(domain.NotifyAssigned, testData())
+	require.NoError(t, err)
+
+	err = email.Send(context.Background(), user, msg)
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, "reviews@example.com", server.from)
+	assert.Equal(t, []string{"bob@example.com"}, server.rcpt)
+
+	parsed, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(server.data)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, msg.Subject, subject)
+	assert.Equal(t, "bob@example.com", parsed.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[contentType] = string(body)
+	}
+	assert.Equal(t, msg.Text, bodies["text/plain"])
+	assert.Equal(t, msg.HTML, bodies["text/html"])
+}
+
+func TestEmail_SendWithoutAddress(t *testing.T) {
+	email := NewEmail(EmailConfig{Host: "127.0.0.1", Port: 1})
+
+	err := email.Send(context.Background(), &domain.User{Member: domain.Member{ID: "u2"}}, &Message{})
+
+	assert.Equal(t, ErrNoAddress, err)
+}
+
+func TestEmail_SendRejected(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		textproto.NewConn(conn).PrintfLine("554 no service")
+	}()
+
+	email := NewEmail(EmailConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "reviews@example.com", Timeout: time.Second})
+	user := &domain.User{Profile: domain.Profile{Email: "bob@example.com"}}
+
+	err = email.Send(context.Background(), user, &Message{Subject: "s", Text: "t", HTML: "h"})
+
+	assert.Error(t, err)
+}
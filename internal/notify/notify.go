@@ -0,0 +1,82 @@
+// Package notify tells users about their reviews through the channels they chose.
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// ErrNoAddress is returned by a channel when the user has no address on it.
+var ErrNoAddress = errors.New("user has no address on the channel")
+
+// Message is a rendered notification. Text and HTML are alternative bodies; channels
+// without markup send Text.
+type Message struct {
+	Kind    string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Channel delivers a message to a user.
+type Channel interface {
+	Send(ctx context.Context, user *domain.User, msg *Message) error
+}
+
+// Data is what the templates are executed with. Other is the reviewer a reassignment
+// replaced or handed the review to.
+type Data struct {
+	User  *domain.User
+	PR    *domain.PullRequest
+	Other string
+}
+
+//go:embed templates
+var files embed.FS
+
+// Every kind has <kind>.txt defining "subject" and "body", and <kind>.html defining "body".
+var (
+	texts = map[string]*texttemplate.Template{}
+	htmls = map[string]*htmltemplate.Template{}
+)
+
+func init() {
+	for _, kind := range domain.NotifyKinds {
+		texts[kind] = texttemplate.Must(texttemplate.ParseFS(files, "templates/"+kind+".txt"))
+		htmls[kind] = htmltemplate.Must(htmltemplate.ParseFS(files, "templates/"+kind+".html"))
+	}
+}
+
+// Render executes the templates of the notification kind.
+func Render(kind string, data *Data) (*Message, error) {
+	text, ok := texts[kind]
+	if !ok {
+		return nil, errors.New("unknown notification " + kind)
+	}
+
+	msg := &Message{Kind: kind}
+	var buf bytes.Buffer
+	if err := text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return nil, err
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := text.ExecuteTemplate(&buf, "body", data); err != nil {
+		return nil, err
+	}
+	msg.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	buf.Reset()
+	if err := htmls[kind].ExecuteTemplate(&buf, "body", data); err != nil {
+		return nil, err
+	}
+	msg.HTML = strings.TrimSpace(buf.String()) + "\n"
+	return msg, nil
+}
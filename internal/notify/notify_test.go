@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData() *Data {
+	due := time.Date(2025, 9, 2, 18, 0, 0, 0, time.UTC)
+	return &Data{
+		User: &domain.User{Member: domain.Member{ID: "u2", Username: "Bob"}},
+		PR: &domain.PullRequest{
+			PullRequestShort: domain.PullRequestShort{ID: "pr-1", Name: "Fix <login>", AuthorID: "u1"},
+			Repository:       "backend",
+			DueAt:            &due,
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Run("assigned", func(t *testing.T) {
+		msg, err := Render(domain.NotifyAssigned, testData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Вас назначили ревьювером: Fix <login>", msg.Subject)
+		assert.Contains(t, msg.Text, "Здравствуйте, Bob!")
+		assert.Contains(t, msg.Text, "«Fix <login>» (pr-1) в backend.")
+		assert.Contains(t, msg.Text, "Ревью ожидается до 02.09.2025 18:00 UTC.")
+		assert.NotContains(t, msg.Text, "передано")
+		assert.Contains(t, msg.HTML, "«Fix &lt;login&gt;»")
+	})
+
+	t.Run("reassigned away", func(t *testing.T) {
+		data := testData()
+		data.Other = "u3"
+		msg, err := Render(domain.NotifyReassignedAway, data)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ревью передано другому: Fix <login>", msg.Subject)
+		assert.Contains(t, msg.Text, "Ревью передано u3.")
+		assert.Contains(t, msg.HTML, "<p>Ревью передано u3.</p>")
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		msg, err := Render(domain.NotifyMerged, testData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Pull request влит: Fix <login>", msg.Subject)
+		assert.Contains(t, msg.Text, "который вы ревьюили, влит.")
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := Render("closed", testData())
+
+		assert.Error(t, err)
+	})
+}
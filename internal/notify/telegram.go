@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"context"
+)
+
+// TelegramSender is the part of the Bot API client the channel needs.
+type TelegramSender interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// ChatLookup returns the Telegram chat the user linked with the bot.
+type ChatLookup func(userID string) (chatID int64, found bool, err error)
+
+// Telegram sends the plain-text body, headed by the subject, to the user's chat with the bot.
+type Telegram struct {
+	sender TelegramSender
+	chatOf ChatLookup
+}
+
+func NewTelegram(sender TelegramSender, chatOf ChatLookup) *Telegram {
+	return &Telegram{sender: sender, chatOf: chatOf}
+}
+
+func (t *Telegram) Send(ctx context.Context, user *domain.User, msg *Message) error {
+	chatID, found, err := t.chatOf(user.ID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNoAddress
+	}
+	return t.sender.SendMessage(ctx, chatID, msg.Subject+"\n\n"+msg.Text)
+}
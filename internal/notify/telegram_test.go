@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentMessage struct {
+	chatID int64
+	text   string
+}
+
+type fakeSender struct {
+	sent []sentMessage
+}
+
+func (f *fakeSender) SendMessage(ctx context.Context, chatID int64, text string) error {
+	f.sent = append(f.sent, sentMessage{chatID, text})
+	return nil
+}
+
+func TestTelegram_Send(t *testing.T) {
+	chats := map[string]int64{"u2": 123456789}
+	lookup := func(userID string) (int64, bool, error) {
+		if userID == "broken" {
+			return 0, false, errors.New("connection refused")
+		}
+		chatID, ok := chats[userID]
+		return chatID, ok, nil
+	}
+	sender := &fakeSender{}
+	channel := NewTelegram(sender, lookup)
+	msg := &Message{Subject: "Вас назначили ревьювером: Fix", Text: "Здравствуйте, Bob!\n"}
+
+	err := channel.Send(context.Background(), &domain.User{Member: domain.Member{ID: "u2"}}, msg)
+	require.NoError(t, err)
+	assert.Equal(t, []sentMessage{{123456789, "Вас назначили ревьювером: Fix\n\nЗдравствуйте, Bob!\n"}}, sender.sent)
+
+	err = channel.Send(context.Background(), &domain.User{Member: domain.Member{ID: "u3"}}, msg)
+	assert.Equal(t, ErrNoAddress, err)
+
+	err = channel.Send(context.Background(), &domain.User{Member: domain.Member{ID: "broken"}}, msg)
+	assert.EqualError(t, err, "connection refused")
+	assert.Len(t, sender.sent, 1)
+}
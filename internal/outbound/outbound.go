@@ -0,0 +1,74 @@
+// Package outbound posts service events to the webhooks operators subscribed.
+package outbound
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a delivery. The signature is "sha256=" followed by the hex HMAC of the body
+// keyed with the subscription secret, as GitHub signs its webhooks.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature-256"
+)
+
+// Sender posts a delivery to its subscription. It returns the HTTP status, 0 when there
+// was no response, and an error unless the status is 2xx.
+type Sender interface {
+	Send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error)
+}
+
+// HTTP is the Sender used in production.
+type HTTP struct {
+	client *http.Client
+}
+
+func NewHTTP(timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTP{client: &http.Client{Timeout: timeout}}
+}
+
+// Sign returns the signature header value of the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *HTTP) Send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer-service")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, delivery.Payload))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("POST %s: %s: %s", sub.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,76 @@
+package outbound
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Send(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(status)
+		if status != http.StatusNoContent {
+			w.Write([]byte("try later"))
+		}
+	}))
+	defer server.Close()
+
+	sub := &domain.WebhookSubscription{ID: 3, URL: server.URL + "/hooks", Secret: "s3cret"}
+	delivery := &domain.WebhookDelivery{
+		ID:      17,
+		Event:   domain.EventPRCreated,
+		Payload: []byte(`{"event":"pr.created","data":{"pull_request_id":"pr-1"}}`),
+	}
+	sender := NewHTTP(time.Second)
+
+	t.Run("signed delivery", func(t *testing.T) {
+		code, err := sender.Send(context.Background(), sub, delivery)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, code)
+		assert.Equal(t, http.MethodPost, got.Method)
+		assert.Equal(t, "/hooks", got.URL.Path)
+		assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+		assert.Equal(t, "pr.created", got.Header.Get(HeaderEvent))
+		assert.Equal(t, "17", got.Header.Get(HeaderDelivery))
+		assert.Equal(t, Sign("s3cret", delivery.Payload), got.Header.Get(HeaderSignature))
+		assert.Equal(t, []byte(delivery.Payload), body)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+
+		code, err := sender.Send(context.Background(), sub, delivery)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "try later")
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		code, err := sender.Send(context.Background(), &domain.WebhookSubscription{URL: "http://127.0.0.1:1"}, delivery)
+
+		assert.Equal(t, 0, code)
+		assert.Error(t, err)
+	})
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+	assert.NotEqual(t, Sign("key", []byte("a")), Sign("other", []byte("a")))
+}
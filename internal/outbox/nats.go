@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS publishes every message on <subject prefix>.<event> of a NATS compatible server,
+// speaking the plain text client protocol. Each publish is followed by a PING, so a
+// message counts as accepted once the server answers PONG.
+type NATS struct {
+	addr    string
+	user    string
+	pass    string
+	token   string
+	prefix  string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewNATS takes a nats://[user:pass@|token@]host:port url.
+func NewNATS(rawURL string, prefix string, timeout time.Duration) (*NATS, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("invalid nats url %q", rawURL)
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	n := &NATS{addr: u.Host, prefix: strings.Trim(prefix, "."), timeout: timeout}
+	if u.Port() == "" {
+		n.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			n.user, n.pass = u.User.Username(), pass
+		} else {
+			n.token = u.User.Username()
+		}
+	}
+	return n, nil
+}
+
+// Subject is where a message of the event goes.
+func (n *NATS) Subject(event string) string {
+	if n.prefix == "" {
+		return event
+	}
+	return n.prefix + "." + event
+}
+
+func (n *NATS) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		if err := n.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := n.publish(n.Subject(msg.EventType), body); err != nil {
+		n.conn.Close()
+		n.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Close drops the connection; the next publish opens a new one.
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
+
+func (n *NATS) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(n.timeout))
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("nats %s: unexpected greeting %q", n.addr, strings.TrimSpace(line))
+	}
+
+	options := map[string]interface{}{"verbose": false, "pedantic": false, "name": "pr-reviewer-service"}
+	if n.user != "" {
+		options["user"], options["pass"] = n.user, n.pass
+	}
+	if n.token != "" {
+		options["auth_token"] = n.token
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+
+	n.conn, n.r = conn, r
+	return nil
+}
+
+func (n *NATS) publish(subject string, body []byte) error {
+	n.conn.SetDeadline(time.Now().Add(n.timeout))
+	if _, err := fmt.Fprintf(n.conn, "PUB %s %d\r\n%s\r\nPING\r\n", subject, len(body), body); err != nil {
+		return err
+	}
+	for {
+		line, err := n.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats %s: %s", n.addr, line)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type published struct {
+	subject string
+	body    string
+}
+
+// fakeNATS accepts connections and answers PINGs; a subject in reject gets -ERR.
+func fakeNATS(t *testing.T, reject string) (string, <-chan string, <-chan published) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	connects := make(chan string, 10)
+	msgs := make(chan published, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				fmt.Fprint(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+				r := bufio.NewReader(conn)
+				lastSubject := ""
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimSpace(line)
+					switch {
+					case strings.HasPrefix(line, "CONNECT "):
+						connects <- strings.TrimPrefix(line, "CONNECT ")
+					case strings.HasPrefix(line, "PUB "):
+						parts := strings.Fields(line)
+						size, _ := strconv.Atoi(parts[2])
+						body := make([]byte, size+2)
+						io.ReadFull(r, body)
+						lastSubject = parts[1]
+						msgs <- published{parts[1], string(body[:size])}
+					case line == "PING":
+						if lastSubject == reject {
+							fmt.Fprint(conn, "-ERR 'Permissions Violation'\r\n")
+							continue
+						}
+						fmt.Fprint(conn, "PONG\r\n")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String(), connects, msgs
+}
+
+func TestNATS_Publish(t *testing.T) {
+	addr, connects, msgs := fakeNATS(t, "reviews.team.changed")
+	sink, err := NewNATS("nats://bot:pw@"+addr, "reviews.", time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Publish(context.Background(), message()))
+	assert.Contains(t, <-connects, `"user":"bot"`)
+	msg := <-msgs
+	assert.Equal(t, "reviews.pr.merged", msg.subject)
+	assert.Contains(t, msg.body, `"aggregate_id":"pr-1"`)
+
+	rejected := message()
+	rejected.EventType = "team.changed"
+	err = sink.Publish(context.Background(), rejected)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Permissions Violation")
+	<-msgs
+
+	require.NoError(t, sink.Publish(context.Background(), message()))
+	<-connects
+	assert.Equal(t, "reviews.pr.merged", (<-msgs).subject)
+}
+
+func TestNewNATS(t *testing.T) {
+	sink, err := NewNATS("nats://s3cret@queue.internal", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "queue.internal:4222", sink.addr)
+	assert.Equal(t, "s3cret", sink.token)
+	assert.Equal(t, "pr.created", sink.Subject("pr.created"))
+
+	_, err = NewNATS("http://queue.internal", "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Package outbox publishes the domain events the service stores in its outbox table.
+package outbox
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink kinds of the configuration.
+const (
+	SinkStdout = "stdout"
+	SinkHTTP   = "http"
+	SinkNATS   = "nats"
+)
+
+// Sink publishes one message; it must only return nil once the message was accepted.
+// Messages may come more than once, consumers dedupe them by id.
+type Sink interface {
+	Publish(ctx context.Context, msg *domain.OutboxMessage) error
+}
+
+// Stdout writes every message as a JSON line, for local runs and log shipping.
+type Stdout struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdout(w io.Writer) *Stdout {
+	return &Stdout{w: w}
+}
+
+func (s *Stdout) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(body, '\n'))
+	return err
+}
+
+// HTTP posts every message to a URL; the message id goes in the Idempotency-Key header.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTP{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (h *HTTP) Publish(ctx context.Context, msg *domain.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strconv.FormatInt(msg.ID, 10))
+	req.Header.Set("X-Event-Type", msg.EventType)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", h.url, resp.Status, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func message() *domain.OutboxMessage {
+	return &domain.OutboxMessage{
+		ID:          42,
+		EventType:   domain.EventPRMerged,
+		AggregateID: "pr-1",
+		Payload:     []byte(`{"pull_request_id":"pr-1","status":"MERGED"}`),
+		CreatedAt:   time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC),
+		Attempts:    3,
+	}
+}
+
+func TestStdout_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewStdout(&buf)
+
+	require.NoError(t, sink.Publish(context.Background(), message()))
+	require.NoError(t, sink.Publish(context.Background(), message()))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"id":42,"event":"pr.merged","aggregate_id":"pr-1",
+		"payload":{"pull_request_id":"pr-1","status":"MERGED"},"created_at":"2025-09-01T10:00:00Z"}`, string(lines[0]))
+}
+
+func TestHTTP_Publish(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTP(server.URL+"/events", time.Second)
+
+	require.NoError(t, sink.Publish(context.Background(), message()))
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/events", got.URL.Path)
+	assert.Equal(t, "42", got.Header.Get("Idempotency-Key"))
+	assert.Equal(t, "pr.merged", got.Header.Get("X-Event-Type"))
+	var decoded domain.OutboxMessage
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, "pr-1", decoded.AggregateID)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, sink.Publish(context.Background(), message()))
+}
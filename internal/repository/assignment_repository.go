@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+)
+
+type AssignmentRepository interface {
+	Add(record *domain.AssignmentRecord) error
+	GetByPR(prID string, userID string) ([]*domain.AssignmentRecord, error)
+}
+
+type assignmentRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewAssignmentRepository(db DBTX, log *logger.Logger) AssignmentRepository {
+	return &assignmentRepo{db: db, log: log}
+}
+
+func (r *assignmentRepo) Add(record *domain.AssignmentRecord) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO reviewer_assignments (pr_id, user_id, action, strategy, rule, rule_detail, fallback,
+			actor, replaced_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.ExecContext(ctx, query, record.PullRequestID, record.UserID, record.Action,
+		record.Strategy, record.Rule, record.RuleDetail, record.Fallback, record.Actor, record.ReplacedUserID)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByPR returns the assignments of the pull request in the order they happened,
+// only those of the user unless userID is empty.
+func (r *assignmentRepo) GetByPR(prID string, userID string) ([]*domain.AssignmentRecord, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, pr_id, user_id, action, strategy, rule, rule_detail, fallback, actor,
+			replaced_user_id, assigned_at
+		FROM reviewer_assignments
+		WHERE pr_id = $1 AND ($2 = '' OR user_id = $2)
+		ORDER BY assigned_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, prID, userID)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*domain.AssignmentRecord{}
+	for rows.Next() {
+		var record domain.AssignmentRecord
+		err := rows.Scan(&record.ID, &record.PullRequestID, &record.UserID, &record.Action,
+			&record.Strategy, &record.Rule, &record.RuleDetail, &record.Fallback, &record.Actor,
+			&record.ReplacedUserID, &record.AssignedAt)
+		if err != nil {
+			r.log.Errorf("failed to scan assignment record: %v", err)
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentRepo_Add(t *testing.T) {
+	t.Run("successful insert", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &assignmentRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO reviewer_assignments (pr_id, user_id, action, strategy, rule, rule_detail, fallback,
+				actor, replaced_user_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`)).WithArgs("pr-1", "u2", "reassign", "random", "author_team", "", true, "u9", "u3").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err = repo.Add(&domain.AssignmentRecord{
+			PullRequestID:  "pr-1",
+			UserID:         "u2",
+			Action:         domain.AssignmentReassigned,
+			Strategy:       "random",
+			Rule:           domain.RuleAuthorTeam,
+			Fallback:       true,
+			Actor:          "u9",
+			ReplacedUserID: "u3",
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &assignmentRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("foreign key violation")
+		mock.ExpectExec(`INSERT INTO reviewer_assignments`).WillReturnError(expectedError)
+
+		err = repo.Add(&domain.AssignmentRecord{PullRequestID: "pr-x"})
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestAssignmentRepo_GetByPR(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &assignmentRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	assignedAt := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "pr_id", "user_id", "action", "strategy", "rule",
+		"rule_detail", "fallback", "actor", "replaced_user_id", "assigned_at"}).
+		AddRow(1, "pr-1", "u2", "create", "random", "reviewer_group", "security", false, "", "", assignedAt).
+		AddRow(2, "pr-1", "u3", "create", "random", "code_owners", "/db/", false, "", "", assignedAt)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM reviewer_assignments
+		WHERE pr_id = $1 AND ($2 = '' OR user_id = $2)
+		ORDER BY assigned_at, id
+	`)).WithArgs("pr-1", "").WillReturnRows(rows)
+
+	result, err := repo.GetByPR("pr-1", "")
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "security", result[0].RuleDetail)
+	assert.Equal(t, domain.RuleCodeOwners, result[1].Rule)
+	assert.Equal(t, assignedAt, result[1].AssignedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type AwayRepository interface {
+	Create(period *domain.AwayPeriod) (*domain.AwayPeriod, error)
+	GetByUser(userID string) ([]*domain.AwayPeriod, error)
+	Delete(id int64) (*domain.AwayPeriod, bool, error)
+	GetStarting(now time.Time) ([]*domain.AwayPeriod, error)
+	GetEnding(now time.Time) ([]*domain.AwayPeriod, error)
+	MarkStarted(id int64, deactivated bool) error
+	MarkFinished(id int64) error
+	PassDeactivated(userID string, now time.Time) (bool, error)
+	IsAway(userID string, now time.Time) (bool, error)
+}
+
+type awayRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewAwayRepository(db DBTX, log *logger.Logger) AwayRepository {
+	return &awayRepo{db: db, log: log}
+}
+
+func (r *awayRepo) Create(period *domain.AwayPeriod) (*domain.AwayPeriod, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO user_away_periods (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, starts_at, ends_at, reason
+	`
+	var newPeriod domain.AwayPeriod
+	err := r.db.QueryRowContext(ctx, query, period.UserID, period.StartsAt, period.EndsAt, period.Reason).
+		Scan(&newPeriod.ID, &newPeriod.UserID, &newPeriod.StartsAt, &newPeriod.EndsAt, &newPeriod.Reason)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return &newPeriod, nil
+}
+
+func (r *awayRepo) GetByUser(userID string) ([]*domain.AwayPeriod, error) {
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason, deactivated
+		FROM user_away_periods
+		WHERE user_id = $1
+		ORDER BY starts_at
+	`
+	return r.query(query, userID)
+}
+
+// Delete removes the period and returns it. Deactivated is set only while the period still
+// holds the user off: it switched them off and hasn't finished.
+func (r *awayRepo) Delete(id int64) (*domain.AwayPeriod, bool, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM user_away_periods
+		WHERE id = $1
+		RETURNING id, user_id, starts_at, ends_at, reason, deactivated AND NOT finished
+	`
+	var period domain.AwayPeriod
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&period.ID, &period.UserID, &period.StartsAt, &period.EndsAt, &period.Reason, &period.Deactivated)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return &period, true, nil
+}
+
+// GetStarting returns windows that are already open but haven't been processed by the scheduler.
+func (r *awayRepo) GetStarting(now time.Time) ([]*domain.AwayPeriod, error) {
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason, deactivated
+		FROM user_away_periods
+		WHERE started = FALSE AND starts_at <= $1 AND ends_at > $1
+		ORDER BY starts_at
+	`
+	return r.query(query, now)
+}
+
+// GetEnding returns windows that are over but haven't been closed by the scheduler.
+func (r *awayRepo) GetEnding(now time.Time) ([]*domain.AwayPeriod, error) {
+	query := `
+		SELECT id, user_id, starts_at, ends_at, reason, deactivated
+		FROM user_away_periods
+		WHERE finished = FALSE AND ends_at <= $1
+		ORDER BY ends_at
+	`
+	return r.query(query, now)
+}
+
+// MarkStarted keeps a deactivated flag that an overlapping window has already passed on.
+func (r *awayRepo) MarkStarted(id int64, deactivated bool) error {
+	ctx := context.Background()
+	query := `
+		UPDATE user_away_periods
+		SET
+			started = TRUE,
+			deactivated = deactivated OR $1
+		WHERE id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, deactivated, id)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *awayRepo) MarkFinished(id int64) error {
+	ctx := context.Background()
+	query := `
+		UPDATE user_away_periods
+		SET
+			started = TRUE,
+			finished = TRUE
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PassDeactivated hands switching the user back on over to their window that is open at
+// now and ends last. It reports whether there was such a window.
+func (r *awayRepo) PassDeactivated(userID string, now time.Time) (bool, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE user_away_periods
+		SET deactivated = TRUE
+		WHERE id = (
+			SELECT id FROM user_away_periods
+			WHERE user_id = $1 AND finished = FALSE AND starts_at <= $2 AND ends_at > $2
+			ORDER BY ends_at DESC
+			LIMIT 1
+		)
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, now)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *awayRepo) IsAway(userID string, now time.Time) (bool, error) {
+	ctx := context.Background()
+	var away bool
+	query := `
+		SELECT EXISTS(SELECT 1 FROM user_away_periods
+		WHERE user_id = $1 AND starts_at <= $2 AND ends_at > $2)
+	`
+	err := r.db.QueryRowContext(ctx, query, userID, now).Scan(&away)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+	return away, nil
+}
+
+func (r *awayRepo) query(query string, args ...interface{}) ([]*domain.AwayPeriod, error) {
+	ctx := context.Background()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []*domain.AwayPeriod{}
+	for rows.Next() {
+		var period domain.AwayPeriod
+		err := rows.Scan(&period.ID, &period.UserID, &period.StartsAt, &period.EndsAt, &period.Reason, &period.Deactivated)
+		if err != nil {
+			r.log.Errorf("failed to scan away period: %v", err)
+			return nil, err
+		}
+		periods = append(periods, &period)
+	}
+
+	return periods, nil
+}
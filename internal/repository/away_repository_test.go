@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwayRepo_Create(t *testing.T) {
+	t.Run("successful away period creation", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		start := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+		end := time.Date(2025, 11, 14, 18, 0, 0, 0, time.UTC)
+		input := &domain.AwayPeriod{UserID: "user-1", StartsAt: start, EndsAt: end, Reason: "vacation"}
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "starts_at", "ends_at", "reason"}).
+			AddRow(1, "user-1", start, end, "vacation")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO user_away_periods (user_id, starts_at, ends_at, reason)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, user_id, starts_at, ends_at, reason
+		`)).WithArgs("user-1", start, end, "vacation").WillReturnRows(rows)
+
+		result, err := repo.Create(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ID)
+		assert.Equal(t, "vacation", result.Reason)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error on creation", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("check constraint violation")
+		mock.ExpectQuery(`INSERT INTO user_away_periods`).WillReturnError(expectedError)
+
+		result, err := repo.Create(&domain.AwayPeriod{UserID: "user-1"})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestAwayRepo_GetStarting(t *testing.T) {
+	t.Run("returns unprocessed open windows", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"id", "user_id", "starts_at", "ends_at", "reason", "deactivated"}).
+			AddRow(1, "user-1", now.Add(-time.Hour), now.Add(time.Hour), "sick", false).
+			AddRow(2, "user-2", now.Add(-time.Minute), now.Add(24*time.Hour), "", false)
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			SELECT id, user_id, starts_at, ends_at, reason, deactivated
+			FROM user_away_periods
+			WHERE started = FALSE AND starts_at <= $1 AND ends_at > $1
+		`)).WithArgs(now).WillReturnRows(rows)
+
+		result, err := repo.GetStarting(now)
+
+		assert.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "user-1", result[0].UserID)
+		assert.Equal(t, "user-2", result[1].UserID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
+
+func TestAwayRepo_Delete(t *testing.T) {
+	t.Run("period deleted", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{db: db, log: &logger.Logger{Logger: log}}
+		startsAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		endsAt := startsAt.AddDate(0, 0, 14)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "starts_at", "ends_at", "reason", "deactivated"}).
+			AddRow(3, "user-1", startsAt, endsAt, "vacation", true)
+		mock.ExpectQuery(`DELETE FROM user_away_periods`).WithArgs(int64(3)).WillReturnRows(rows)
+
+		period, deleted, err := repo.Delete(3)
+
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, "user-1", period.UserID)
+		assert.True(t, period.Deactivated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("period not found", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{db: db, log: &logger.Logger{Logger: log}}
+
+		mock.ExpectQuery(`DELETE FROM user_away_periods`).WithArgs(int64(4)).
+			WillReturnError(sql.ErrNoRows)
+
+		period, deleted, err := repo.Delete(4)
+
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+		assert.Nil(t, period)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAwayRepo_PassDeactivated(t *testing.T) {
+	t.Run("passed to open period", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{db: db, log: &logger.Logger{Logger: log}}
+		now := time.Now()
+
+		mock.ExpectExec(`UPDATE user_away_periods\s+SET deactivated = TRUE`).WithArgs("user-1", now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		passed, err := repo.PassDeactivated("user-1", now)
+
+		assert.NoError(t, err)
+		assert.True(t, passed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no open period", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{db: db, log: &logger.Logger{Logger: log}}
+		now := time.Now()
+
+		mock.ExpectExec(`UPDATE user_away_periods\s+SET deactivated = TRUE`).WithArgs("user-1", now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		passed, err := repo.PassDeactivated("user-1", now)
+
+		assert.NoError(t, err)
+		assert.False(t, passed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAwayRepo_IsAway(t *testing.T) {
+	t.Run("user is away", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &awayRepo{db: db, log: &logger.Logger{Logger: log}}
+		now := time.Now()
+
+		rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
+		mock.ExpectQuery(`SELECT EXISTS`).WithArgs("user-1", now).WillReturnRows(rows)
+
+		away, err := repo.IsAway("user-1", now)
+
+		assert.NoError(t, err)
+		assert.True(t, away)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
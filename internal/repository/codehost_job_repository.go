@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CodeHostJobRepository interface {
+	Enqueue(job *domain.CodeHostJob) (*domain.CodeHostJob, error)
+	GetDue(now time.Time, limit int) ([]*domain.CodeHostJob, error)
+	MarkDone(id int64, attempts int) error
+	Reschedule(id int64, attempts int, next time.Time, lastError string) error
+	MarkFailed(id int64, attempts int, lastError string) error
+}
+
+type codeHostJobRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewCodeHostJobRepository(db DBTX, log *logger.Logger) CodeHostJobRepository {
+	return &codeHostJobRepo{db: db, log: log}
+}
+
+const codeHostJobColumns = `id, pr_id, action, reviewers, status, attempts, next_attempt_at, last_error`
+
+func scanCodeHostJob(row rowScanner) (*domain.CodeHostJob, error) {
+	var job domain.CodeHostJob
+	err := row.Scan(&job.ID, &job.PullRequestID, &job.Action, pq.Array(&job.Reviewers), &job.Status,
+		&job.Attempts, &job.NextAttemptAt, &job.LastError)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *codeHostJobRepo) Enqueue(job *domain.CodeHostJob) (*domain.CodeHostJob, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO code_host_jobs (pr_id, action, reviewers)
+		VALUES ($1, $2, $3)
+		RETURNING ` + codeHostJobColumns + `
+	`
+	newJob, err := scanCodeHostJob(r.db.QueryRowContext(ctx, query, job.PullRequestID, job.Action,
+		pq.Array(job.Reviewers)))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return newJob, nil
+}
+
+// GetDue returns the pending jobs whose next attempt is due, oldest first.
+func (r *codeHostJobRepo) GetDue(now time.Time, limit int) ([]*domain.CodeHostJob, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + codeHostJobColumns + `
+		FROM code_host_jobs
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*domain.CodeHostJob{}
+	for rows.Next() {
+		job, err := scanCodeHostJob(rows)
+		if err != nil {
+			r.log.Errorf("failed to scan code host job: %v", err)
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (r *codeHostJobRepo) MarkDone(id int64, attempts int) error {
+	ctx := context.Background()
+	query := `
+		UPDATE code_host_jobs
+		SET status = 'done', attempts = $2, last_error = ''
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *codeHostJobRepo) Reschedule(id int64, attempts int, next time.Time, lastError string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE code_host_jobs
+		SET attempts = $2, next_attempt_at = $3, last_error = $4
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts, next, lastError)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *codeHostJobRepo) MarkFailed(id int64, attempts int, lastError string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE code_host_jobs
+		SET status = 'failed', attempts = $2, last_error = $3
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts, lastError)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
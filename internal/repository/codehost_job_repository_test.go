@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var codeHostJobRowColumns = []string{"id", "pr_id", "action", "reviewers", "status", "attempts",
+	"next_attempt_at", "last_error"}
+
+func TestCodeHostJobRepo_Enqueue(t *testing.T) {
+	t.Run("successful enqueue", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &codeHostJobRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(codeHostJobRowColumns).
+			AddRow(1, "acme/api#42", "request", "{u2,u3}", "pending", 0, now, "")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO code_host_jobs (pr_id, action, reviewers)
+			VALUES ($1, $2, $3)
+		`)).WithArgs("acme/api#42", "request", `{"u2","u3"}`).WillReturnRows(rows)
+
+		result, err := repo.Enqueue(&domain.CodeHostJob{
+			PullRequestID: "acme/api#42",
+			Action:        domain.CodeHostRequest,
+			Reviewers:     []string{"u2", "u3"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ID)
+		assert.Equal(t, domain.JobPending, result.Status)
+		assert.Equal(t, []string{"u2", "u3"}, result.Reviewers)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &codeHostJobRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`INSERT INTO code_host_jobs`).WillReturnError(expectedError)
+
+		result, err := repo.Enqueue(&domain.CodeHostJob{PullRequestID: "acme/api#42"})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestCodeHostJobRepo_GetDue(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &codeHostJobRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(codeHostJobRowColumns).
+		AddRow(1, "acme/api#42", "request", "{u2}", "pending", 2, now, "502 Bad Gateway").
+		AddRow(2, "acme/api#42", "remove", "{u3}", "pending", 0, now, "")
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM code_host_jobs
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2
+	`)).WithArgs(now, 50).WillReturnRows(rows)
+
+	result, err := repo.GetDue(now, 50)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 2, result[0].Attempts)
+	assert.Equal(t, "502 Bad Gateway", result[0].LastError)
+	assert.Equal(t, domain.CodeHostRemove, result[1].Action)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestCodeHostJobRepo_Updates(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &codeHostJobRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	next := time.Date(2025, 9, 1, 10, 4, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`SET status = 'done', attempts = $2, last_error = ''`)).
+		WithArgs(int64(1), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SET attempts = $2, next_attempt_at = $3, last_error = $4`)).
+		WithArgs(int64(2), 3, next, "timeout").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SET status = 'failed', attempts = $2, last_error = $3`)).
+		WithArgs(int64(3), 10, "404 Not Found").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDone(1, 1))
+	assert.NoError(t, repo.Reschedule(2, 3, next, "timeout"))
+	assert.NoError(t, repo.MarkFailed(3, 10, "404 Not Found"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
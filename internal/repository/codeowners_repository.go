@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+)
+
+type CodeOwnersRepository interface {
+	Save(repository string, content string) error
+	Get(repository string) (string, bool, error)
+}
+
+type codeOwnersRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewCodeOwnersRepository(db DBTX, log *logger.Logger) CodeOwnersRepository {
+	return &codeOwnersRepo{db: db, log: log}
+}
+
+func (r *codeOwnersRepo) Save(repository string, content string) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO code_owners (repository, content)
+		VALUES ($1, $2)
+		ON CONFLICT (repository) DO UPDATE
+		SET
+			content = EXCLUDED.content,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.ExecContext(ctx, query, repository, content)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the ownership file of the repository and whether it was uploaded.
+func (r *codeOwnersRepo) Get(repository string) (string, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT content
+		FROM code_owners
+		WHERE repository = $1
+	`
+	var content string
+	err := r.db.QueryRowContext(ctx, query, repository).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return "", false, err
+	}
+
+	return content, true, nil
+}
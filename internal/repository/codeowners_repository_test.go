@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwnersRepo_Save(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &codeOwnersRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO code_owners (repository, content)
+		VALUES ($1, $2)
+		ON CONFLICT (repository) DO UPDATE
+	`)).WithArgs("backend-api", "* @backend").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Save("backend-api", "* @backend")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestCodeOwnersRepo_Get(t *testing.T) {
+	t.Run("uploaded file", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &codeOwnersRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"content"}).AddRow("* @backend")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			SELECT content
+			FROM code_owners
+			WHERE repository = $1
+		`)).WithArgs("backend-api").WillReturnRows(rows)
+
+		content, found, err := repo.Get("backend-api")
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "* @backend", content)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &codeOwnersRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`FROM code_owners`).WithArgs("web").
+			WillReturnRows(sqlmock.NewRows([]string{"content"}))
+
+		_, found, err := repo.Get("web")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &codeOwnersRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`FROM code_owners`).WillReturnError(expectedError)
+
+		_, _, err = repo.Get("web")
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
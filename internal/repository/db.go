@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is what the repositories run their queries on: the database itself or a
+// transaction, so that several changes can be stored together.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+
+	"github.com/lib/pq"
+)
+
+type ExclusionRepository interface {
+	Create(rule *domain.ExclusionRule) (*domain.ExclusionRule, error)
+	GetAll() ([]*domain.ExclusionRule, error)
+	Delete(id int64) (bool, error)
+}
+
+type exclusionRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewExclusionRepository(db DBTX, log *logger.Logger) ExclusionRepository {
+	return &exclusionRepo{db: db, log: log}
+}
+
+func (r *exclusionRepo) Create(rule *domain.ExclusionRule) (*domain.ExclusionRule, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO exclusion_rules (kind, members, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id, kind, members, reason
+	`
+	var newRule domain.ExclusionRule
+	err := r.db.QueryRowContext(ctx, query, rule.Kind, pq.Array(rule.Members), rule.Reason).
+		Scan(&newRule.ID, &newRule.Kind, pq.Array(&newRule.Members), &newRule.Reason)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return &newRule, nil
+}
+
+func (r *exclusionRepo) GetAll() ([]*domain.ExclusionRule, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, kind, members, reason
+		FROM exclusion_rules
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*domain.ExclusionRule{}
+	for rows.Next() {
+		var rule domain.ExclusionRule
+		err := rows.Scan(&rule.ID, &rule.Kind, pq.Array(&rule.Members), &rule.Reason)
+		if err != nil {
+			r.log.Errorf("failed to scan exclusion rule: %v", err)
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+func (r *exclusionRepo) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM exclusion_rules
+		WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusionRepo_Create(t *testing.T) {
+	t.Run("successful creation", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &exclusionRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "kind", "members", "reason"}).
+			AddRow(1, "no_cross_review", "{u1,u2}", "manager")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO exclusion_rules (kind, members, reason)
+			VALUES ($1, $2, $3)
+			RETURNING id, kind, members, reason
+		`)).WithArgs("no_cross_review", `{"u1","u2"}`, "manager").WillReturnRows(rows)
+
+		result, err := repo.Create(&domain.ExclusionRule{
+			Kind:    domain.ExclusionNoCrossReview,
+			Members: []string{"u1", "u2"},
+			Reason:  "manager",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ID)
+		assert.Equal(t, []string{"u1", "u2"}, result.Members)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &exclusionRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("check constraint violated")
+		mock.ExpectQuery(`INSERT INTO exclusion_rules`).WillReturnError(expectedError)
+
+		result, err := repo.Create(&domain.ExclusionRule{Kind: "other"})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestExclusionRepo_GetAll(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &exclusionRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "kind", "members", "reason"}).
+		AddRow(1, "no_cross_review", "{u1,u2}", "manager").
+		AddRow(2, "not_together", "{u3,u4,u5}", "")
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT id, kind, members, reason
+		FROM exclusion_rules
+	`)).WillReturnRows(rows)
+
+	result, err := repo.GetAll()
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, domain.ExclusionNotTogether, result[1].Kind)
+	assert.Equal(t, []string{"u3", "u4", "u5"}, result[1].Members)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestExclusionRepo_Delete(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &exclusionRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		DELETE FROM exclusion_rules
+		WHERE id = $1
+	`)).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	deleted, err := repo.Delete(1)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
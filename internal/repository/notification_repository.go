@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type NotificationRepository interface {
+	GetPreferences(userID string) (*domain.NotificationPreferences, bool, error)
+	SavePreferences(prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error)
+}
+
+type notificationRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewNotificationRepository(db DBTX, log *logger.Logger) NotificationRepository {
+	return &notificationRepo{db: db, log: log}
+}
+
+const preferencesColumns = `user_id, channels, events`
+
+func scanPreferences(row rowScanner) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	err := row.Scan(&prefs.UserID, pq.Array(&prefs.Channels), pq.Array(&prefs.Events))
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *notificationRepo) GetPreferences(userID string) (*domain.NotificationPreferences, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + preferencesColumns + `
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+	prefs, err := scanPreferences(r.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return prefs, true, nil
+}
+
+func (r *notificationRepo) SavePreferences(prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO notification_preferences (user_id, channels, events)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channels = EXCLUDED.channels, events = EXCLUDED.events, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + preferencesColumns + `
+	`
+	saved, err := scanPreferences(r.db.QueryRowContext(ctx, query,
+		prefs.UserID, pq.Array(prefs.Channels), pq.Array(prefs.Events)))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
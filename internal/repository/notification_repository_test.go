@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var preferencesRowColumns = []string{"user_id", "channels", "events"}
+
+func TestNotificationRepo_GetPreferences(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &notificationRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows(preferencesRowColumns).AddRow("u1", "{email}", "{assigned,merged}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			FROM notification_preferences
+			WHERE user_id = $1
+		`)).WithArgs("u1").WillReturnRows(rows)
+
+		result, found, err := repo.GetPreferences("u1")
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []string{"email"}, result.Channels)
+		assert.Equal(t, []string{"assigned", "merged"}, result.Events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &notificationRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`FROM notification_preferences`).WithArgs("u2").WillReturnError(sql.ErrNoRows)
+
+		result, found, err := repo.GetPreferences("u2")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
+
+func TestNotificationRepo_SavePreferences(t *testing.T) {
+	t.Run("successful save", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &notificationRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows(preferencesRowColumns).AddRow("u1", "{email}", "{merged}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO notification_preferences (user_id, channels, events)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id) DO UPDATE
+		`)).WithArgs("u1", `{"email"}`, `{"merged"}`).WillReturnRows(rows)
+
+		result, err := repo.SavePreferences(&domain.NotificationPreferences{
+			UserID:   "u1",
+			Channels: []string{domain.ChannelEmail},
+			Events:   []string{domain.NotifyMerged},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "u1", result.UserID)
+		assert.Equal(t, []string{"merged"}, result.Events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &notificationRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`INSERT INTO notification_preferences`).WillReturnError(expectedError)
+
+		result, err := repo.SavePreferences(&domain.NotificationPreferences{UserID: "u1"})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
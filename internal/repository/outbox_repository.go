@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+)
+
+type OutboxRepository interface {
+	Add(msg *domain.OutboxMessage) error
+	GetUnpublished(limit int) ([]*domain.OutboxMessage, error)
+	MarkPublished(id int64, at time.Time) error
+	MarkFailed(id int64, lastError string) error
+	DeletePublished(before time.Time) (int64, error)
+}
+
+type outboxRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewOutboxRepository(db DBTX, log *logger.Logger) OutboxRepository {
+	return &outboxRepo{db: db, log: log}
+}
+
+func (r *outboxRepo) Add(msg *domain.OutboxMessage) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO outbox (event_type, aggregate_id, payload)
+		VALUES ($1, $2, $3)
+	`
+	_, err := r.db.ExecContext(ctx, query, msg.EventType, msg.AggregateID, []byte(msg.Payload))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUnpublished returns the oldest messages not published yet, in the order they were
+// written.
+func (r *outboxRepo) GetUnpublished(limit int) ([]*domain.OutboxMessage, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, event_type, aggregate_id, payload, created_at, attempts, last_error
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	msgs := []*domain.OutboxMessage{}
+	for rows.Next() {
+		var msg domain.OutboxMessage
+		var payload []byte
+		err := rows.Scan(&msg.ID, &msg.EventType, &msg.AggregateID, &payload, &msg.CreatedAt, &msg.Attempts, &msg.LastError)
+		if err != nil {
+			r.log.Errorf("failed to scan outbox message: %v", err)
+			return nil, err
+		}
+		msg.Payload = payload
+		msgs = append(msgs, &msg)
+	}
+
+	return msgs, nil
+}
+
+func (r *outboxRepo) MarkPublished(id int64, at time.Time) error {
+	ctx := context.Background()
+	query := `
+		UPDATE outbox
+		SET published_at = $2, attempts = attempts + 1, last_error = ''
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, at)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed attempt; the message stays unpublished.
+func (r *outboxRepo) MarkFailed(id int64, lastError string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, lastError)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePublished drops messages published before the given moment.
+func (r *outboxRepo) DeletePublished(before time.Time) (int64, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM outbox
+		WHERE published_at IS NOT NULL AND published_at < $1
+	`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return 0, err
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepo_Add(t *testing.T) {
+	t.Run("successful add", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &outboxRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		payload := []byte(`{"pull_request_id":"pr-1"}`)
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO outbox (event_type, aggregate_id, payload)
+			VALUES ($1, $2, $3)
+		`)).WithArgs("pr.merged", "pr-1", payload).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err = repo.Add(&domain.OutboxMessage{EventType: domain.EventPRMerged, AggregateID: "pr-1", Payload: payload})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("runs inside a transaction", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO outbox`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		repo := NewOutboxRepository(tx, &logger.Logger{Logger: log})
+
+		err = repo.Add(&domain.OutboxMessage{EventType: domain.EventTeamChanged, AggregateID: "backend", Payload: []byte(`{}`)})
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &outboxRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("current transaction is aborted")
+		mock.ExpectExec(`INSERT INTO outbox`).WillReturnError(expectedError)
+
+		err = repo.Add(&domain.OutboxMessage{EventType: domain.EventPRCreated, Payload: []byte(`{}`)})
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestOutboxRepo_GetUnpublished(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &outboxRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "created_at", "attempts", "last_error"}).
+		AddRow(4, "pr.created", "pr-1", []byte(`{"pull_request_id":"pr-1"}`), now, 0, "").
+		AddRow(5, "reviewer.assigned", "pr-1", []byte(`{"user_id":"u2"}`), now, 2, "connection refused")
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`)).WithArgs(100).WillReturnRows(rows)
+
+	result, err := repo.GetUnpublished(100)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, int64(4), result[0].ID)
+	assert.JSONEq(t, `{"user_id":"u2"}`, string(result[1].Payload))
+	assert.Equal(t, 2, result[1].Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestOutboxRepo_MarkPublished(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &outboxRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	at := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta(`
+		UPDATE outbox
+		SET published_at = $2, attempts = attempts + 1, last_error = ''
+		WHERE id = $1
+	`)).WithArgs(int64(4), at).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.MarkPublished(4, at)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestOutboxRepo_DeletePublished(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &outboxRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	before := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`
+		DELETE FROM outbox
+		WHERE published_at IS NOT NULL AND published_at < $1
+	`)).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 12))
+
+	deleted, err := repo.DeletePublished(before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
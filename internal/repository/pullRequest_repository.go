@@ -4,96 +4,67 @@ import (
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/package/logger"
 	"context"
-	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type PullRequestRepository interface {
-	Create(pr *domain.PullRequestShort) (*domain.PullRequest, error)
+	Create(pr *domain.PullRequest) (*domain.PullRequest, error)
 	Merge(id string) (*domain.PullRequest, error)
-	Reassign(id string, oldRevID string) (*domain.PullRequest, error)
+	SetStatus(id string, status string) (*domain.PullRequest, error)
 	GetByID(id string) (*domain.PullRequest, error)
 	GetReviewrs(id string) ([]string, error)
-	RemoveReviewer(id string, revID string) error
+	GetRequiredReviewers(id string) ([]*domain.RequiredReviewer, error)
+	AddReviewer(id string, revID string, group string) error
+	UnassignReviewer(id string, revID string, replacedBy string) (bool, error)
+	RecordVerdict(id string, userID string, verdict string, at time.Time) error
+	GetReviewerHistory(id string) ([]*domain.ReviewerHistoryEntry, error)
 	CheckPRExist(id string) (bool, error)
+	GetPairCounts(authorID string, since time.Time) (map[string]int, error)
+	GetPairings(teamName string, since time.Time) ([]*domain.Pairing, error)
 }
 
 type pullRequestRepo struct {
-	db  *sql.DB
+	db  DBTX
 	log *logger.Logger
 }
 
-func NewPullRequestRepository(db *sql.DB, log *logger.Logger) PullRequestRepository {
+func NewPullRequestRepository(db DBTX, log *logger.Logger) PullRequestRepository {
 	return &pullRequestRepo{db: db, log: log}
 }
 
-func (r *pullRequestRepo) Create(pr *domain.PullRequestShort) (*domain.PullRequest, error) {
-	ctx := context.Background()
-	query := `
-		INSERT INTO pull_requests (id, name, author_id, status)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, author_id, status, created_at
-	`
-	newPR := domain.PullRequest{AssignedReviewers: []string{}}
-	err := r.db.QueryRowContext(ctx, query, pr.ID, pr.Name, pr.AuthorID, pr.Status).Scan(&newPR.ID, &newPR.Name, &newPR.AuthorID, &newPR.Status, &newPR.CreatedAt)
+// prColumns is the full pull_requests row, read by scanPR.
+const prColumns = `id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+	co_author_ids`
+
+func scanPR(row rowScanner) (*domain.PullRequest, error) {
+	pr := domain.PullRequest{AssignedReviewers: []string{}}
+	err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.DueAt,
+		pq.Array(&pr.Labels), &pr.Repository, pq.Array(&pr.ChangedFiles), pq.Array(&pr.CoAuthorIDs))
 	if err != nil {
-		r.log.Errorf("failed to exec query: %v", err)
 		return nil, err
 	}
+	return &pr, nil
+}
 
-	var cnt int
-	query = `
-		SELECT COUNT(*)
-		FROM users
-		WHERE team_name IN(
-			SELECT team_name
-			FROM users
-			WHERE id = $1
-		) AND is_active = TRUE
+func (r *pullRequestRepo) Create(pr *domain.PullRequest) (*domain.PullRequest, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO pull_requests (id, name, author_id, status, due_at, labels, repository, changed_files,
+			co_author_ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + prColumns + `
 	`
-	err = r.db.QueryRowContext(ctx, query, newPR.AuthorID).Scan(&cnt)
+	newPR, err := scanPR(r.db.QueryRowContext(ctx, query, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.DueAt,
+		pq.Array(nonNil(pr.Labels)), pr.Repository, pq.Array(nonNil(pr.ChangedFiles)),
+		pq.Array(nonNil(pr.CoAuthorIDs))))
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
 		return nil, err
 	}
-	if cnt > 1 {
-		places := 1
-		if cnt >= 3 {
-			places = 2
-		}
-		for true {
-			id, err := r.getRandomUserID(ctx)
-			if err != nil {
-				r.log.Errorf("failed to get random user id: %v", err)
-				return nil, err
-			}
-			query = `
-				INSERT INTO pr_reviewrs (user_id, pr_id)
-				VALUES ($1, $2)
-			`
-			if id != newPR.AuthorID && len(newPR.AssignedReviewers) == 0 {
-				newPR.AssignedReviewers = append(newPR.AssignedReviewers, id)
-				_, err = r.db.ExecContext(ctx, query, id, newPR.ID)
-				if err != nil {
-					r.log.Errorf("failed to exec query: %v", err)
-					return nil, err
-				}
-			} else {
-				if id != newPR.AuthorID && id != newPR.AssignedReviewers[0] {
-					newPR.AssignedReviewers = append(newPR.AssignedReviewers, id)
-					_, err = r.db.ExecContext(ctx, query, id, newPR.ID)
-					if err != nil {
-						r.log.Errorf("failed to exec query: %v", err)
-						return nil, err
-					}
-				}
-			}
-			if len(newPR.AssignedReviewers) == places {
-				break
-			}
-		}
-	}
 
-	return &newPR, nil
+	return newPR, nil
 }
 
 func (r *pullRequestRepo) Merge(id string) (*domain.PullRequest, error) {
@@ -104,90 +75,88 @@ func (r *pullRequestRepo) Merge(id string) (*domain.PullRequest, error) {
 			status = 'MERGED',
 			merged_at = CURRENT_TIMESTAMP
 		WHERE id = $1
-		RETURNING id, name, author_id, status, created_at, merged_at
+		RETURNING ` + prColumns + `
 	`
-	newPR := domain.PullRequest{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&newPR.ID, &newPR.Name, &newPR.AuthorID, &newPR.Status, &newPR.CreatedAt, &newPR.MergedAt)
+	newPR, err := scanPR(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
 		return nil, err
 	}
 
-	return &newPR, nil
+	return newPR, nil
 }
 
-func (r *pullRequestRepo) Reassign(id string, oldRevID string) (*domain.PullRequest, error) {
+// SetStatus moves the pull request between OPEN and CLOSED; merging goes through Merge.
+func (r *pullRequestRepo) SetStatus(id string, status string) (*domain.PullRequest, error) {
 	ctx := context.Background()
-	pr, err := r.GetByID(id)
+	query := `
+		UPDATE pull_requests
+		SET status = $2
+		WHERE id = $1
+		RETURNING ` + prColumns + `
+	`
+	newPR, err := scanPR(r.db.QueryRowContext(ctx, query, id, status))
 	if err != nil {
-		r.log.Errorf("failed to get pr by id: %v", err)
+		r.log.Errorf("failed to exec query: %v", err)
 		return nil, err
 	}
 
-	usersID, err := r.GetReviewrs(id)
-	if err != nil {
-		r.log.Errorf("failed to get reviewers: %v", err)
-		return nil, err
-	}
+	return newPR, nil
+}
 
-	cnt, err := r.countReviewrs(ctx, pr)
+// AddReviewer assigns the reviewer; group is the mandatory group they were picked from,
+// empty for optional reviewers. Someone who already reviewed unasked becomes assigned.
+func (r *pullRequestRepo) AddReviewer(id string, revID string, group string) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO pr_reviewrs (user_id, pr_id, required, required_group)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pr_id, user_id) WHERE unassigned_at IS NULL
+		DO UPDATE SET assigned = TRUE, required = EXCLUDED.required, required_group = EXCLUDED.required_group
+	`
+	_, err := r.db.ExecContext(ctx, query, revID, id, group != "", group)
 	if err != nil {
-		r.log.Errorf("failed to count reviewers: %v", err)
-		return nil, err
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
 	}
 
-	if cnt-1-len(usersID) >= 1 {
-		for true {
-			newRevID, err := r.getRandomUserID(ctx)
-			if err != nil {
-				r.log.Errorf("failed to get random user id: %v", err)
-				return nil, err
-			}
-			query := `
-				INSERT INTO pr_reviewrs (user_id, pr_id)
-				VALUES ($1, $2)
-			`
-			if newRevID != pr.AuthorID {
-				flag := true
-				for i := 0; i < len(usersID); i++ {
-					if newRevID == usersID[i] {
-						flag = false
-						break
-					}
-				}
-				if flag {
-					_, err = r.db.ExecContext(ctx, query, newRevID, id)
-					if err != nil {
-						r.log.Errorf("failed to exec query: %v", err)
-						return nil, err
-					}
-					err = r.RemoveReviewer(id, oldRevID)
-					if err != nil {
-						r.log.Errorf("failed to remove reviewer: %v", err)
-						return nil, err
-					}
-					break
-				}
-			}
-		}
-	}
+	return nil
+}
 
-	usersID, err = r.GetReviewrs(id)
+// UnassignReviewer ends the current assignment of the reviewer, keeping it in the history
+// along with who replaced them.
+func (r *pullRequestRepo) UnassignReviewer(id string, revID string, replacedBy string) (bool, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE pr_reviewrs
+		SET unassigned_at = CURRENT_TIMESTAMP, replaced_by = $3
+		WHERE pr_id = $1 AND user_id = $2 AND unassigned_at IS NULL AND assigned = TRUE
+	`
+	res, err := r.db.ExecContext(ctx, query, id, revID, replacedBy)
 	if err != nil {
-		return nil, err
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
 	}
-	pr.AssignedReviewers = usersID
 
-	return pr, nil
+	return affected > 0, nil
 }
 
-func (r *pullRequestRepo) RemoveReviewer(id string, revID string) error {
+// RecordVerdict stores the latest review of the user on the pull request, adding them as
+// an unassigned reviewer if they were not on it.
+func (r *pullRequestRepo) RecordVerdict(id string, userID string, verdict string, at time.Time) error {
 	ctx := context.Background()
 	query := `
-		DELETE FROM pr_reviewrs 
-		WHERE pr_id = $1 AND user_id = $2
+		INSERT INTO pr_reviewrs (user_id, pr_id, assigned, verdict, reviewed_at)
+		VALUES ($1, $2, FALSE, $3, $4)
+		ON CONFLICT (pr_id, user_id) WHERE unassigned_at IS NULL
+		DO UPDATE SET verdict = EXCLUDED.verdict, reviewed_at = EXCLUDED.reviewed_at
 	`
-	_, err := r.db.ExecContext(ctx, query, id, revID)
+	_, err := r.db.ExecContext(ctx, query, userID, id, verdict, at)
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
 		return err
@@ -199,12 +168,11 @@ func (r *pullRequestRepo) RemoveReviewer(id string, revID string) error {
 func (r *pullRequestRepo) GetByID(id string) (*domain.PullRequest, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT ` + prColumns + `
 		FROM pull_requests
 		WHERE id = $1
 	`
-	newPR := domain.PullRequest{AssignedReviewers: []string{}}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&newPR.ID, &newPR.Name, &newPR.AuthorID, &newPR.Status, &newPR.CreatedAt, &newPR.MergedAt)
+	newPR, err := scanPR(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
 		return nil, err
@@ -216,7 +184,19 @@ func (r *pullRequestRepo) GetByID(id string) (*domain.PullRequest, error) {
 		return nil, err
 	}
 
-	return &newPR, nil
+	newPR.RequiredReviewers, err = r.GetRequiredReviewers(id)
+	if err != nil {
+		r.log.Errorf("failed to get required reviewers: %v", err)
+		return nil, err
+	}
+
+	newPR.ReviewerHistory, err = r.GetReviewerHistory(id)
+	if err != nil {
+		r.log.Errorf("failed to get reviewer history: %v", err)
+		return nil, err
+	}
+
+	return newPR, nil
 }
 
 func (r *pullRequestRepo) GetReviewrs(id string) ([]string, error) {
@@ -224,7 +204,7 @@ func (r *pullRequestRepo) GetReviewrs(id string) ([]string, error) {
 	query := `
 		SELECT user_id
 		FROM pr_reviewrs
-		WHERE pr_id = $1
+		WHERE pr_id = $1 AND unassigned_at IS NULL AND assigned = TRUE
 	`
 	var usersID []string
 	rows, err := r.db.QueryContext(ctx, query, id)
@@ -246,23 +226,65 @@ func (r *pullRequestRepo) GetReviewrs(id string) ([]string, error) {
 	return usersID, nil
 }
 
-func (r *pullRequestRepo) getRandomUserID(ctx context.Context) (string, error) {
+// GetReviewerHistory returns every reviewer the pull request had, replaced and unassigned
+// ones included, in the order they came.
+func (r *pullRequestRepo) GetReviewerHistory(id string) ([]*domain.ReviewerHistoryEntry, error) {
+	ctx := context.Background()
+	query := `
+		SELECT user_id, required_group, assigned_at, unassigned_at, replaced_by, assigned, verdict, reviewed_at
+		FROM pr_reviewrs
+		WHERE pr_id = $1
+		ORDER BY assigned_at, user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*domain.ReviewerHistoryEntry{}
+	for rows.Next() {
+		var entry domain.ReviewerHistoryEntry
+		err := rows.Scan(&entry.UserID, &entry.Group, &entry.AssignedAt, &entry.UnassignedAt, &entry.ReplacedBy,
+			&entry.Assigned, &entry.Verdict, &entry.ReviewedAt)
+		if err != nil {
+			r.log.Errorf("failed to scan reviewer history: %v", err)
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+
+	return history, nil
+}
+
+func (r *pullRequestRepo) GetRequiredReviewers(id string) ([]*domain.RequiredReviewer, error) {
+	ctx := context.Background()
 	query := `
-        SELECT id
-        FROM users
-		WHERE is_active = true 
-        ORDER BY RANDOM() 
-        LIMIT 1
-    `
-
-	var id string
-	err := r.db.QueryRowContext(ctx, query).Scan(&id)
+		SELECT user_id, required_group
+		FROM pr_reviewrs
+		WHERE pr_id = $1 AND required = TRUE AND unassigned_at IS NULL
+		ORDER BY required_group, user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
-		return "", err
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := []*domain.RequiredReviewer{}
+	for rows.Next() {
+		var reviewer domain.RequiredReviewer
+		err := rows.Scan(&reviewer.UserID, &reviewer.Group)
+		if err != nil {
+			r.log.Errorf("failed to scan required reviewer: %v", err)
+			return nil, err
+		}
+		reviewers = append(reviewers, &reviewer)
 	}
 
-	return id, nil
+	return reviewers, nil
 }
 
 func (r *pullRequestRepo) CheckPRExist(id string) (bool, error) {
@@ -280,22 +302,79 @@ func (r *pullRequestRepo) CheckPRExist(id string) (bool, error) {
 	return exists, nil
 }
 
-func (r *pullRequestRepo) countReviewrs(ctx context.Context, rp *domain.PullRequest) (int, error) {
-	var cnt int
+// GetPairCounts counts, per reviewer, the pull requests the author wrote or co-wrote since
+// then that they review.
+func (r *pullRequestRepo) GetPairCounts(authorID string, since time.Time) (map[string]int, error) {
+	ctx := context.Background()
+	query := `
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewrs r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE (p.author_id = $1 OR $1 = ANY(p.co_author_ids)) AND p.created_at >= $2
+			AND r.unassigned_at IS NULL
+		GROUP BY r.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, authorID, since)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var userID string
+		var count int
+		err := rows.Scan(&userID, &count)
+		if err != nil {
+			r.log.Errorf("failed to scan pair count: %v", err)
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
+	return counts, nil
+}
+
+// GetPairings returns the author-reviewer matrix of the team's pull requests created since then.
+// Co-authors count as authors.
+func (r *pullRequestRepo) GetPairings(teamName string, since time.Time) ([]*domain.Pairing, error) {
+	ctx := context.Background()
 	query := `
-		SELECT COUNT(*)
-		FROM users
-		WHERE team_name IN(
-			SELECT team_name
-			FROM users
-			WHERE id = $1
-		) AND is_active = TRUE
+		SELECT a.author_id, r.user_id, COUNT(*)
+		FROM pull_requests p
+		CROSS JOIN LATERAL unnest(array_prepend(p.author_id::text, p.co_author_ids)) AS a(author_id)
+		JOIN pr_reviewrs r ON r.pr_id = p.id AND r.unassigned_at IS NULL
+		JOIN users u ON u.id = a.author_id
+		WHERE u.team_name = $1 AND p.created_at >= $2
+		GROUP BY a.author_id, r.user_id
+		ORDER BY a.author_id, r.user_id
 	`
-	err := r.db.QueryRowContext(ctx, query, rp.AuthorID).Scan(&cnt)
+	rows, err := r.db.QueryContext(ctx, query, teamName, since)
 	if err != nil {
 		r.log.Errorf("failed to exec query: %v", err)
-		return 0, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairings := []*domain.Pairing{}
+	for rows.Next() {
+		var pairing domain.Pairing
+		err := rows.Scan(&pairing.AuthorID, &pairing.ReviewerID, &pairing.Reviews)
+		if err != nil {
+			r.log.Errorf("failed to scan pairing: %v", err)
+			return nil, err
+		}
+		pairings = append(pairings, &pairing)
 	}
 
-	return cnt, nil
+	return pairings, nil
+}
+
+// nonNil keeps NOT NULL array columns from receiving NULL.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
 }
@@ -15,8 +15,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var historyColumns = []string{"user_id", "required_group", "assigned_at", "unassigned_at", "replaced_by",
+	"assigned", "verdict", "reviewed_at"}
+
+var prRowColumns = []string{"id", "name", "author_id", "status", "created_at", "merged_at", "due_at",
+	"labels", "repository", "changed_files", "co_author_ids"}
+
 func TestPullRequestRepo_Create(t *testing.T) {
-	t.Run("successful PR creation without reviewers", func(t *testing.T) {
+	t.Run("successful PR creation", func(t *testing.T) {
 		log, hook := test.NewNullLogger()
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -24,39 +30,42 @@ func TestPullRequestRepo_Create(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
-		inputPR := &domain.PullRequestShort{
-			ID:       "pr-1",
-			Name:     "Feature A",
-			AuthorID: "author-1",
-			Status:   "open",
+		dueAt := time.Date(2025, 11, 5, 12, 0, 0, 0, time.UTC)
+		inputPR := &domain.PullRequest{
+			PullRequestShort: domain.PullRequestShort{
+				ID:       "pr-1",
+				Name:     "Feature A",
+				AuthorID: "author-1",
+				Status:   "open",
+			},
+			Repository:   "backend-api",
+			Labels:       []string{"db", "security"},
+			ChangedFiles: []string{"internal/db.go"},
+			CoAuthorIDs:  []string{"co-1"},
+			DueAt:        &dueAt,
 		}
 
-		rows := sqlmock.NewRows([]string{"id", "name", "author_id", "status", "created_at"}).
-			AddRow("pr-1", "Feature A", "author-1", "open", time.Now())
-		mock.ExpectQuery(regexp.QuoteMeta(`
-            INSERT INTO pull_requests (id, name, author_id, status)
-            VALUES ($1, $2, $3, $4)
-            RETURNING id, name, author_id, status, created_at
-        `)).WithArgs("pr-1", "Feature A", "author-1", "open").WillReturnRows(rows)
-
-		countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+		rows := sqlmock.NewRows(prRowColumns).
+			AddRow("pr-1", "Feature A", "author-1", "open", time.Now(), nil, dueAt, "{db,security}", "backend-api", "{internal/db.go}", "{co-1}")
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT COUNT(*)
-            FROM users
-            WHERE team_name IN(
-                SELECT team_name
-                FROM users
-                WHERE id = $1
-            ) AND is_active = TRUE
-        `)).WithArgs("author-1").WillReturnRows(countRows)
+            INSERT INTO pull_requests (id, name, author_id, status, due_at, labels, repository, changed_files,
+                co_author_ids)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
+        `)).WithArgs("pr-1", "Feature A", "author-1", "open", dueAt, `{"db","security"}`, "backend-api", `{"internal/db.go"}`, `{"co-1"}`).WillReturnRows(rows)
 
 		result, err := repo.Create(inputPR)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "pr-1", result.ID)
+		assert.Equal(t, dueAt, *result.DueAt)
+		assert.Equal(t, []string{"db", "security"}, result.Labels)
+		assert.Equal(t, []string{"internal/db.go"}, result.ChangedFiles)
+		assert.Equal(t, []string{"co-1"}, result.CoAuthorIDs)
 		assert.Empty(t, result.AssignedReviewers)
 		assert.NoError(t, mock.ExpectationsWereMet())
 		assert.Len(t, hook.AllEntries(), 0)
@@ -70,22 +79,32 @@ func TestPullRequestRepo_Create(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
-		inputPR := &domain.PullRequestShort{
-			ID:       "pr-1",
-			Name:     "Feature A",
-			AuthorID: "author-1",
-			Status:   "open",
+		dueAt := time.Date(2025, 11, 5, 12, 0, 0, 0, time.UTC)
+		inputPR := &domain.PullRequest{
+			PullRequestShort: domain.PullRequestShort{
+				ID:       "pr-1",
+				Name:     "Feature A",
+				AuthorID: "author-1",
+				Status:   "open",
+			},
+			Repository:   "backend-api",
+			Labels:       []string{"db", "security"},
+			ChangedFiles: []string{"internal/db.go"},
+			CoAuthorIDs:  []string{"co-1"},
+			DueAt:        &dueAt,
 		}
 
 		expectedError := errors.New("unique constraint violation")
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            INSERT INTO pull_requests (id, name, author_id, status)
-            VALUES ($1, $2, $3, $4)
-            RETURNING id, name, author_id, status, created_at
-        `)).WithArgs("pr-1", "Feature A", "author-1", "open").WillReturnError(expectedError)
+            INSERT INTO pull_requests (id, name, author_id, status, due_at, labels, repository, changed_files,
+                co_author_ids)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
+        `)).WithArgs("pr-1", "Feature A", "author-1", "open", dueAt, `{"db","security"}`, "backend-api", `{"internal/db.go"}`, `{"co-1"}`).WillReturnError(expectedError)
 
 		result, err := repo.Create(inputPR)
 
@@ -105,20 +124,21 @@ func TestPullRequestRepo_Merge(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "pr-1"
 
-		rows := sqlmock.NewRows([]string{"id", "name", "author_id", "status", "created_at", "merged_at"}).
-			AddRow("pr-1", "Feature A", "author-1", "MERGED", time.Now(), time.Now())
+		rows := sqlmock.NewRows(prRowColumns).
+			AddRow("pr-1", "Feature A", "author-1", "MERGED", time.Now(), time.Now(), nil, "{}", "", "{}", "{}")
 		mock.ExpectQuery(regexp.QuoteMeta(`
             UPDATE pull_requests
             SET
                 status = 'MERGED',
                 merged_at = CURRENT_TIMESTAMP
             WHERE id = $1
-            RETURNING id, name, author_id, status, created_at, merged_at
+            RETURNING id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
         `)).WithArgs("pr-1").WillReturnRows(rows)
 
 		result, err := repo.Merge(prID)
@@ -138,7 +158,7 @@ func TestPullRequestRepo_Merge(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "non-existent-pr"
@@ -149,7 +169,8 @@ func TestPullRequestRepo_Merge(t *testing.T) {
                 status = 'MERGED',
                 merged_at = CURRENT_TIMESTAMP
             WHERE id = $1
-            RETURNING id, name, author_id, status, created_at, merged_at
+            RETURNING id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
         `)).WithArgs("non-existent-pr").WillReturnError(sql.ErrNoRows)
 
 		result, err := repo.Merge(prID)
@@ -161,6 +182,57 @@ func TestPullRequestRepo_Merge(t *testing.T) {
 	})
 }
 
+func TestPullRequestRepo_SetStatus(t *testing.T) {
+	t.Run("successfully close PR", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows(prRowColumns).
+			AddRow("pr-1", "Feature A", "author-1", "CLOSED", time.Now(), nil, nil, "{}", "", "{}", "{}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+            UPDATE pull_requests
+            SET status = $2
+            WHERE id = $1
+            RETURNING id, name, author_id, status
+        `)).WithArgs("pr-1", "CLOSED").WillReturnRows(rows)
+
+		result, err := repo.SetStatus("pr-1", "CLOSED")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CLOSED", result.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("PR not found", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`UPDATE pull_requests`).WithArgs("pr-x", "OPEN").WillReturnError(sql.ErrNoRows)
+
+		result, err := repo.SetStatus("pr-x", "OPEN")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
 func TestPullRequestRepo_GetByID(t *testing.T) {
 	t.Run("successfully get PR by ID", func(t *testing.T) {
 		log, hook := test.NewNullLogger()
@@ -170,15 +242,16 @@ func TestPullRequestRepo_GetByID(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "pr-1"
 
-		rows := sqlmock.NewRows([]string{"id", "name", "author_id", "status", "created_at", "merged_at"}).
-			AddRow("pr-1", "Feature A", "author-1", "open", time.Now(), nil)
+		rows := sqlmock.NewRows(prRowColumns).
+			AddRow("pr-1", "Feature A", "author-1", "open", time.Now(), nil, nil, "{}", "", "{}", "{}")
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, name, author_id, status, created_at, merged_at
+            SELECT id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
             FROM pull_requests
             WHERE id = $1
         `)).WithArgs("pr-1").WillReturnRows(rows)
@@ -189,14 +262,31 @@ func TestPullRequestRepo_GetByID(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`
             SELECT user_id
             FROM pr_reviewrs
-            WHERE pr_id = $1
+            WHERE pr_id = $1 AND unassigned_at IS NULL AND assigned = TRUE
         `)).WithArgs("pr-1").WillReturnRows(reviewerRows)
 
+		requiredRows := sqlmock.NewRows([]string{"user_id", "required_group"}).
+			AddRow("reviewer-2", "security")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+            SELECT user_id, required_group
+            FROM pr_reviewrs
+            WHERE pr_id = $1 AND required = TRUE AND unassigned_at IS NULL
+        `)).WithArgs("pr-1").WillReturnRows(requiredRows)
+
+		historyRows := sqlmock.NewRows(historyColumns).
+			AddRow("reviewer-3", "", time.Now(), time.Now(), "reviewer-1", true, "", nil).
+			AddRow("reviewer-1", "", time.Now(), nil, "", true, "approved", time.Now())
+		mock.ExpectQuery(`FROM pr_reviewrs`).WithArgs("pr-1").WillReturnRows(historyRows)
+
 		result, err := repo.GetByID(prID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "pr-1", result.ID)
 		assert.Len(t, result.AssignedReviewers, 2)
+		require.Len(t, result.RequiredReviewers, 1)
+		assert.Equal(t, "security", result.RequiredReviewers[0].Group)
+		require.Len(t, result.ReviewerHistory, 2)
+		assert.Equal(t, "reviewer-1", result.ReviewerHistory[0].ReplacedBy)
 		assert.NoError(t, mock.ExpectationsWereMet())
 		assert.Len(t, hook.AllEntries(), 0)
 	})
@@ -209,13 +299,14 @@ func TestPullRequestRepo_GetByID(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "non-existent-pr"
 
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, name, author_id, status, created_at, merged_at
+            SELECT id, name, author_id, status, created_at, merged_at, due_at, labels, repository, changed_files,
+            co_author_ids
             FROM pull_requests
             WHERE id = $1
         `)).WithArgs("non-existent-pr").WillReturnError(sql.ErrNoRows)
@@ -229,8 +320,8 @@ func TestPullRequestRepo_GetByID(t *testing.T) {
 	})
 }
 
-func TestPullRequestRepo_RemoveReviewer(t *testing.T) {
-	t.Run("successfully remove reviewer", func(t *testing.T) {
+func TestPullRequestRepo_AddReviewer(t *testing.T) {
+	t.Run("successfully add reviewer", func(t *testing.T) {
 		log, hook := test.NewNullLogger()
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -238,25 +329,91 @@ func TestPullRequestRepo_RemoveReviewer(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
-		prID := "pr-1"
-		reviewerID := "reviewer-1"
+		mock.ExpectExec(regexp.QuoteMeta(`
+            INSERT INTO pr_reviewrs (user_id, pr_id, required, required_group)
+            VALUES ($1, $2, $3, $4)
+        `)).WithArgs("reviewer-1", "pr-1", false, "").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.AddReviewer("pr-1", "reviewer-1", "")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("successfully add required reviewer", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectExec(`INSERT INTO pr_reviewrs`).
+			WithArgs("reviewer-1", "pr-1", true, "security").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.AddReviewer("pr-1", "reviewer-1", "security")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error on add reviewer", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("duplicate key")
+		mock.ExpectExec(`INSERT INTO pr_reviewrs`).WithArgs("reviewer-1", "pr-1", false, "").WillReturnError(expectedError)
+
+		err = repo.AddReviewer("pr-1", "reviewer-1", "")
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestPullRequestRepo_UnassignReviewer(t *testing.T) {
+	t.Run("successfully unassign reviewer", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
 
 		mock.ExpectExec(regexp.QuoteMeta(`
-            DELETE FROM pr_reviewrs 
-            WHERE pr_id = $1 AND user_id = $2
-        `)).WithArgs("pr-1", "reviewer-1").WillReturnResult(sqlmock.NewResult(0, 1))
+            UPDATE pr_reviewrs
+            SET unassigned_at = CURRENT_TIMESTAMP, replaced_by = $3
+            WHERE pr_id = $1 AND user_id = $2 AND unassigned_at IS NULL AND assigned = TRUE
+        `)).WithArgs("pr-1", "reviewer-1", "reviewer-2").WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err = repo.RemoveReviewer(prID, reviewerID)
+		unassigned, err := repo.UnassignReviewer("pr-1", "reviewer-1", "reviewer-2")
 
 		assert.NoError(t, err)
+		assert.True(t, unassigned)
 		assert.NoError(t, mock.ExpectationsWereMet())
 		assert.Len(t, hook.AllEntries(), 0)
 	})
 
-	t.Run("database error on remove reviewer", func(t *testing.T) {
+	t.Run("reviewer not assigned", func(t *testing.T) {
 		log, hook := test.NewNullLogger()
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -264,19 +421,36 @@ func TestPullRequestRepo_RemoveReviewer(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
-		prID := "pr-1"
-		reviewerID := "reviewer-1"
+		mock.ExpectExec(`UPDATE pr_reviewrs`).
+			WithArgs("pr-1", "ghost", "reviewer-2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		unassigned, err := repo.UnassignReviewer("pr-1", "ghost", "reviewer-2")
+
+		assert.NoError(t, err)
+		assert.False(t, unassigned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error on unassign reviewer", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
 
 		expectedError := errors.New("connection failed")
-		mock.ExpectExec(regexp.QuoteMeta(`
-            DELETE FROM pr_reviewrs 
-            WHERE pr_id = $1 AND user_id = $2
-        `)).WithArgs("pr-1", "reviewer-1").WillReturnError(expectedError)
+		mock.ExpectExec(`UPDATE pr_reviewrs`).
+			WithArgs("pr-1", "reviewer-1", "reviewer-2").WillReturnError(expectedError)
 
-		err = repo.RemoveReviewer(prID, reviewerID)
+		_, err = repo.UnassignReviewer("pr-1", "reviewer-1", "reviewer-2")
 
 		assert.Error(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -284,6 +458,98 @@ func TestPullRequestRepo_RemoveReviewer(t *testing.T) {
 	})
 }
 
+func TestPullRequestRepo_RecordVerdict(t *testing.T) {
+	t.Run("successfully record verdict", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		at := time.Date(2025, 9, 2, 14, 3, 11, 0, time.UTC)
+		mock.ExpectExec(regexp.QuoteMeta(`
+            INSERT INTO pr_reviewrs (user_id, pr_id, assigned, verdict, reviewed_at)
+            VALUES ($1, $2, FALSE, $3, $4)
+            ON CONFLICT (pr_id, user_id) WHERE unassigned_at IS NULL
+            DO UPDATE SET verdict = EXCLUDED.verdict, reviewed_at = EXCLUDED.reviewed_at
+        `)).WithArgs("reviewer-1", "pr-1", "approved", at).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.RecordVerdict("pr-1", "reviewer-1", domain.VerdictApproved, at)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("foreign key violation")
+		mock.ExpectExec(`INSERT INTO pr_reviewrs`).WillReturnError(expectedError)
+
+		err = repo.RecordVerdict("pr-1", "ghost", domain.VerdictApproved, time.Now())
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestPullRequestRepo_GetReviewerHistory(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &pullRequestRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	assignedAt := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	unassignedAt := assignedAt.Add(2 * time.Hour)
+	reviewedAt := unassignedAt.Add(time.Hour)
+	rows := sqlmock.NewRows(historyColumns).
+		AddRow("reviewer-1", "", assignedAt, unassignedAt, "reviewer-3", true, "", nil).
+		AddRow("reviewer-2", "security", assignedAt, nil, "", true, "changes_requested", reviewedAt).
+		AddRow("reviewer-3", "", unassignedAt, nil, "", true, "approved", reviewedAt).
+		AddRow("volunteer", "", reviewedAt, nil, "", false, "approved", reviewedAt)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+        SELECT user_id, required_group, assigned_at, unassigned_at, replaced_by, assigned, verdict, reviewed_at
+        FROM pr_reviewrs
+        WHERE pr_id = $1
+        ORDER BY assigned_at, user_id
+    `)).WithArgs("pr-1").WillReturnRows(rows)
+
+	result, err := repo.GetReviewerHistory("pr-1")
+
+	assert.NoError(t, err)
+	require.Len(t, result, 4)
+	assert.Equal(t, unassignedAt, *result[0].UnassignedAt)
+	assert.Empty(t, result[0].Verdict)
+	assert.Nil(t, result[0].ReviewedAt)
+	assert.Equal(t, domain.VerdictChangesRequested, result[1].Verdict)
+	assert.Equal(t, reviewedAt, *result[2].ReviewedAt)
+	assert.False(t, result[3].Assigned)
+	assert.Equal(t, "reviewer-3", result[0].ReplacedBy)
+	assert.Equal(t, "security", result[1].Group)
+	assert.Nil(t, result[1].UnassignedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
 func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 	t.Run("successfully get reviewers", func(t *testing.T) {
 		log, hook := test.NewNullLogger()
@@ -293,7 +559,7 @@ func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "pr-1"
@@ -304,7 +570,7 @@ func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`
             SELECT user_id
             FROM pr_reviewrs
-            WHERE pr_id = $1
+            WHERE pr_id = $1 AND unassigned_at IS NULL AND assigned = TRUE
         `)).WithArgs("pr-1").WillReturnRows(rows)
 
 		result, err := repo.GetReviewrs(prID)
@@ -324,7 +590,7 @@ func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 
 		repo := &pullRequestRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		prID := "pr-1"
@@ -333,7 +599,7 @@ func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`
             SELECT user_id
             FROM pr_reviewrs
-            WHERE pr_id = $1
+            WHERE pr_id = $1 AND unassigned_at IS NULL AND assigned = TRUE
         `)).WithArgs("pr-1").WillReturnRows(rows)
 
 		result, err := repo.GetReviewrs(prID)
@@ -345,3 +611,91 @@ func TestPullRequestRepo_GetReviewrs(t *testing.T) {
 		assert.Len(t, hook.AllEntries(), 0)
 	})
 }
+
+func TestPullRequestRepo_GetPairCounts(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &pullRequestRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	since := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"user_id", "count"}).
+		AddRow("u2", 7).
+		AddRow("u3", 1)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewrs r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE (p.author_id = $1 OR $1 = ANY(p.co_author_ids)) AND p.created_at >= $2
+			AND r.unassigned_at IS NULL
+		GROUP BY r.user_id
+	`)).WithArgs("u1", since).WillReturnRows(rows)
+
+	result, err := repo.GetPairCounts("u1", since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"u2": 7, "u3": 1}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestPullRequestRepo_GetPairings(t *testing.T) {
+	t.Run("matrix of the team", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		since := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"author_id", "user_id", "count"}).
+			AddRow("u1", "u2", 7).
+			AddRow("u2", "u1", 3)
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			CROSS JOIN LATERAL unnest(array_prepend(p.author_id::text, p.co_author_ids)) AS a(author_id)
+			JOIN pr_reviewrs r ON r.pr_id = p.id AND r.unassigned_at IS NULL
+			JOIN users u ON u.id = a.author_id
+			WHERE u.team_name = $1 AND p.created_at >= $2
+			GROUP BY a.author_id, r.user_id
+		`)).WithArgs("backend", since).WillReturnRows(rows)
+
+		result, err := repo.GetPairings("backend", since)
+
+		assert.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, &domain.Pairing{AuthorID: "u1", ReviewerID: "u2", Reviews: 7}, result[0])
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &pullRequestRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`JOIN pr_reviewrs`).WillReturnError(expectedError)
+
+		result, err := repo.GetPairings("backend", time.Now())
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type RepositoryRepository interface {
+	Create(repo *domain.Repository) error
+	Update(repo *domain.Repository) error
+	Get(name string) (*domain.Repository, bool, error)
+}
+
+type repositoryRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewRepositoryRepository(db DBTX, log *logger.Logger) RepositoryRepository {
+	return &repositoryRepo{db: db, log: log}
+}
+
+func (r *repositoryRepo) Create(repo *domain.Repository) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO repositories (name, reviewer_count, strategy, required_approvals)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, repo.Name, repo.Policy.ReviewerCount,
+		repo.Policy.Strategy, repo.Policy.RequiredApprovals)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return r.setTeams(ctx, repo.Name, repo.Teams)
+}
+
+// Update replaces the policy and the teams of the repository.
+func (r *repositoryRepo) Update(repo *domain.Repository) error {
+	ctx := context.Background()
+	query := `
+		UPDATE repositories
+		SET
+			reviewer_count = $1,
+			strategy = $2,
+			required_approvals = $3
+		WHERE name = $4
+	`
+	_, err := r.db.ExecContext(ctx, query, repo.Policy.ReviewerCount, repo.Policy.Strategy,
+		repo.Policy.RequiredApprovals, repo.Name)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return r.setTeams(ctx, repo.Name, repo.Teams)
+}
+
+func (r *repositoryRepo) setTeams(ctx context.Context, name string, teams []string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM repository_teams WHERE repository = $1`, name)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+	if len(teams) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO repository_teams (repository, team_name)
+		SELECT $1, unnest($2::varchar[])
+	`
+	_, err = r.db.ExecContext(ctx, query, name, pq.Array(teams))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the repository with its teams and whether it is registered.
+func (r *repositoryRepo) Get(name string) (*domain.Repository, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT r.name, r.reviewer_count, r.strategy, r.required_approvals,
+			COALESCE(array_agg(t.team_name ORDER BY t.team_name) FILTER (WHERE t.team_name IS NOT NULL), '{}')
+		FROM repositories r
+		LEFT JOIN repository_teams t ON t.repository = r.name
+		WHERE r.name = $1
+		GROUP BY r.name
+	`
+	repo := &domain.Repository{}
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&repo.Name, &repo.Policy.ReviewerCount,
+		&repo.Policy.Strategy, &repo.Policy.RequiredApprovals, pq.Array(&repo.Teams))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return repo, true, nil
+}
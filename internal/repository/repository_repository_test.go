@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryRepo_Create(t *testing.T) {
+	t.Run("repository with teams", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &repositoryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		input := &domain.Repository{
+			Name:   "backend-api",
+			Teams:  []string{"backend", "dba"},
+			Policy: domain.RepositoryPolicy{ReviewerCount: 3, Strategy: "working_hours", RequiredApprovals: 2},
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO repositories (name, reviewer_count, strategy, required_approvals)
+			VALUES ($1, $2, $3, $4)
+		`)).WithArgs("backend-api", 3, "working_hours", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM repository_teams WHERE repository = $1`)).
+			WithArgs("backend-api").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO repository_teams (repository, team_name)
+			SELECT $1, unnest($2::varchar[])
+		`)).WithArgs("backend-api", `{"backend","dba"}`).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err = repo.Create(input)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &repositoryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("duplicate key")
+		mock.ExpectExec(`INSERT INTO repositories`).WillReturnError(expectedError)
+
+		err = repo.Create(&domain.Repository{Name: "backend-api"})
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestRepositoryRepo_Update(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &repositoryRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		UPDATE repositories
+		SET
+			reviewer_count = $1,
+			strategy = $2,
+			required_approvals = $3
+		WHERE name = $4
+	`)).WithArgs(1, "", 1, "web").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM repository_teams`).
+		WithArgs("web").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Update(&domain.Repository{
+		Name:   "web",
+		Policy: domain.RepositoryPolicy{ReviewerCount: 1, RequiredApprovals: 1},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestRepositoryRepo_Get(t *testing.T) {
+	t.Run("registered repository", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &repositoryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"name", "reviewer_count", "strategy", "required_approvals", "teams"}).
+			AddRow("backend-api", 3, "working_hours", 2, "{backend,dba}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			FROM repositories r
+			LEFT JOIN repository_teams t ON t.repository = r.name
+			WHERE r.name = $1
+		`)).WithArgs("backend-api").WillReturnRows(rows)
+
+		result, found, err := repo.Get("backend-api")
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []string{"backend", "dba"}, result.Teams)
+		assert.Equal(t, 3, result.Policy.ReviewerCount)
+		assert.Equal(t, "working_hours", result.Policy.Strategy)
+		assert.Equal(t, 2, result.Policy.RequiredApprovals)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &repositoryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`FROM repositories`).WithArgs("web").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "reviewer_count", "strategy", "required_approvals", "teams"}))
+
+		result, found, err := repo.Get("web")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
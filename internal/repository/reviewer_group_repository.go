@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type ReviewerGroupRepository interface {
+	Save(group *domain.ReviewerGroup) error
+	GetAll() ([]*domain.ReviewerGroup, error)
+	GetByName(name string) (*domain.ReviewerGroup, bool, error)
+	GetApplicable(teamName string, repository string) ([]*domain.ReviewerGroup, error)
+	Delete(name string) (bool, error)
+}
+
+type reviewerGroupRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewReviewerGroupRepository(db DBTX, log *logger.Logger) ReviewerGroupRepository {
+	return &reviewerGroupRepo{db: db, log: log}
+}
+
+const reviewerGroupColumns = `name, team_name, repository, labels, members, count`
+
+func scanReviewerGroup(row rowScanner) (*domain.ReviewerGroup, error) {
+	var group domain.ReviewerGroup
+	err := row.Scan(&group.Name, &group.TeamName, &group.Repository, pq.Array(&group.Labels),
+		pq.Array(&group.Members), &group.Count)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *reviewerGroupRepo) Save(group *domain.ReviewerGroup) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO reviewer_groups (name, team_name, repository, labels, members, count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET
+			team_name = EXCLUDED.team_name,
+			repository = EXCLUDED.repository,
+			labels = EXCLUDED.labels,
+			members = EXCLUDED.members,
+			count = EXCLUDED.count,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.ExecContext(ctx, query, group.Name, group.TeamName, group.Repository,
+		pq.Array(nonNil(group.Labels)), pq.Array(nonNil(group.Members)), group.Count)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *reviewerGroupRepo) GetAll() ([]*domain.ReviewerGroup, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + reviewerGroupColumns + `
+		FROM reviewer_groups
+		ORDER BY name
+	`
+	return r.queryGroups(ctx, query)
+}
+
+func (r *reviewerGroupRepo) GetByName(name string) (*domain.ReviewerGroup, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + reviewerGroupColumns + `
+		FROM reviewer_groups
+		WHERE name = $1
+	`
+	group, err := scanReviewerGroup(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return group, true, nil
+}
+
+// GetApplicable returns the groups scoped to the team and the repository or to neither.
+// Labels are left to the caller.
+func (r *reviewerGroupRepo) GetApplicable(teamName string, repository string) ([]*domain.ReviewerGroup, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + reviewerGroupColumns + `
+		FROM reviewer_groups
+		WHERE (team_name = '' OR team_name = $1)
+		AND (repository = '' OR repository = $2)
+		ORDER BY name
+	`
+	return r.queryGroups(ctx, query, teamName, repository)
+}
+
+func (r *reviewerGroupRepo) Delete(name string) (bool, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM reviewer_groups
+		WHERE name = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, name)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *reviewerGroupRepo) queryGroups(ctx context.Context, query string, args ...interface{}) ([]*domain.ReviewerGroup, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []*domain.ReviewerGroup{}
+	for rows.Next() {
+		group, err := scanReviewerGroup(rows)
+		if err != nil {
+			r.log.Errorf("failed to scan reviewer group: %v", err)
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
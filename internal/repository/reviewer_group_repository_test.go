@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reviewerGroupRowColumns = []string{"name", "team_name", "repository", "labels", "members", "count"}
+
+func TestReviewerGroupRepo_Save(t *testing.T) {
+	t.Run("successful save", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &reviewerGroupRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		group := &domain.ReviewerGroup{
+			Name:    "security",
+			Labels:  []string{"security"},
+			Members: []string{"u7", "u8"},
+			Count:   1,
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO reviewer_groups (name, team_name, repository, labels, members, count)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (name) DO UPDATE
+		`)).WithArgs("security", "", "", `{"security"}`, `{"u7","u8"}`, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.Save(group)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &reviewerGroupRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectExec(`INSERT INTO reviewer_groups`).WillReturnError(expectedError)
+
+		err = repo.Save(&domain.ReviewerGroup{Name: "security", Count: 1})
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestReviewerGroupRepo_GetApplicable(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &reviewerGroupRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	rows := sqlmock.NewRows(reviewerGroupRowColumns).
+		AddRow("dba", "", "backend-api", "{}", "{dba}", 1).
+		AddRow("security", "", "", "{security,auth}", "{u7,u8}", 2)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM reviewer_groups
+		WHERE (team_name = '' OR team_name = $1)
+		AND (repository = '' OR repository = $2)
+	`)).WithArgs("backend", "backend-api").WillReturnRows(rows)
+
+	result, err := repo.GetApplicable("backend", "backend-api")
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "backend-api", result[0].Repository)
+	assert.Equal(t, []string{"security", "auth"}, result[1].Labels)
+	assert.Equal(t, []string{"u7", "u8"}, result[1].Members)
+	assert.Equal(t, 2, result[1].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestReviewerGroupRepo_GetByName(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &reviewerGroupRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectQuery(`FROM reviewer_groups`).WithArgs("security").
+		WillReturnRows(sqlmock.NewRows(reviewerGroupRowColumns))
+
+	result, found, err := repo.GetByName("security")
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestReviewerGroupRepo_Delete(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &reviewerGroupRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		DELETE FROM reviewer_groups
+		WHERE name = $1
+	`)).WithArgs("security").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.Delete("security")
+
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
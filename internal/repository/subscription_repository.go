@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type SubscriptionRepository interface {
+	Create(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	GetAll() ([]*domain.WebhookSubscription, error)
+	Get(id int64) (*domain.WebhookSubscription, bool, error)
+	GetByEvent(event string) ([]*domain.WebhookSubscription, error)
+	Delete(id int64) (bool, error)
+}
+
+type subscriptionRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewSubscriptionRepository(db DBTX, log *logger.Logger) SubscriptionRepository {
+	return &subscriptionRepo{db: db, log: log}
+}
+
+const subscriptionColumns = `id, url, secret, events, created_at`
+
+func scanSubscription(row rowScanner) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.Events), &sub.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *subscriptionRepo) Create(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING ` + subscriptionColumns + `
+	`
+	newSub, err := scanSubscription(r.db.QueryRowContext(ctx, query, sub.URL, sub.Secret, pq.Array(sub.Events)))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return newSub, nil
+}
+
+func (r *subscriptionRepo) GetAll() ([]*domain.WebhookSubscription, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+	return r.query(ctx, query)
+}
+
+func (r *subscriptionRepo) Get(id int64) (*domain.WebhookSubscription, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return sub, true, nil
+}
+
+// GetByEvent returns the subscriptions that asked for the event.
+func (r *subscriptionRepo) GetByEvent(event string) ([]*domain.WebhookSubscription, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE $1 = ANY(events)
+		ORDER BY id
+	`
+	return r.query(ctx, query, event)
+}
+
+func (r *subscriptionRepo) Delete(id int64) (bool, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *subscriptionRepo) query(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			r.log.Errorf("failed to scan subscription: %v", err)
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, nil
+}
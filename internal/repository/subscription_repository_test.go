@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var subscriptionRowColumns = []string{"id", "url", "secret", "events", "created_at"}
+
+func TestSubscriptionRepo_Create(t *testing.T) {
+	t.Run("successful create", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &subscriptionRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(subscriptionRowColumns).
+			AddRow(1, "https://bots.example.com/hook", "s3cret", "{pr.created,pr.merged}", now)
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO webhook_subscriptions (url, secret, events)
+			VALUES ($1, $2, $3)
+		`)).WithArgs("https://bots.example.com/hook", "s3cret", `{"pr.created","pr.merged"}`).WillReturnRows(rows)
+
+		result, err := repo.Create(&domain.WebhookSubscription{
+			URL:    "https://bots.example.com/hook",
+			Secret: "s3cret",
+			Events: []string{domain.EventPRCreated, domain.EventPRMerged},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.ID)
+		assert.Equal(t, []string{"pr.created", "pr.merged"}, result.Events)
+		assert.Equal(t, now, result.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &subscriptionRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`INSERT INTO webhook_subscriptions`).WillReturnError(expectedError)
+
+		result, err := repo.Create(&domain.WebhookSubscription{URL: "https://bots.example.com/hook"})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestSubscriptionRepo_GetByEvent(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &subscriptionRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows(subscriptionRowColumns).
+		AddRow(1, "https://a.example.com", "a", "{pr.created}", now).
+		AddRow(4, "https://b.example.com", "b", "{pr.merged,pr.created}", now)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM webhook_subscriptions
+		WHERE $1 = ANY(events)
+		ORDER BY id
+	`)).WithArgs("pr.created").WillReturnRows(rows)
+
+	result, err := repo.GetByEvent(domain.EventPRCreated)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, int64(4), result[1].ID)
+	assert.Equal(t, "b", result[1].Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestSubscriptionRepo_Get(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &subscriptionRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectQuery(`FROM webhook_subscriptions`).WithArgs(int64(9)).WillReturnError(sql.ErrNoRows)
+
+	result, found, err := repo.Get(9)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestSubscriptionRepo_Delete(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &subscriptionRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhook_subscriptions`)).
+		WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhook_subscriptions`)).
+		WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.Delete(1)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(2)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
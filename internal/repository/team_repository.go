@@ -4,22 +4,27 @@ import (
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/package/logger"
 	"context"
-	"database/sql"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
 type TeamRepository interface {
 	Create(team *domain.Team) (*domain.Team, error)
 	GetByName(teamName string) (*domain.Team, error)
 	CheckExist(teamName string) (bool, error)
+	GetPolicy(teamName string) (*domain.TeamPolicy, error)
+	SetPolicy(teamName string, policy *domain.TeamPolicy) error
+	AddHolidays(teamName string, holidays []*domain.Holiday) error
+	GetHolidays(teamNames []string, from string) ([]*domain.Holiday, error)
 }
 
 type teamRepo struct {
-	db  *sql.DB
+	db  DBTX
 	log *logger.Logger
 }
 
-func NewTeamRepository(db *sql.DB, log *logger.Logger) TeamRepository {
+func NewTeamRepository(db DBTX, log *logger.Logger) TeamRepository {
 	return &teamRepo{db: db, log: log}
 }
 
@@ -85,3 +90,86 @@ func (r *teamRepo) CheckExist(teamName string) (bool, error) {
 	}
 	return exists, nil
 }
+
+func (r *teamRepo) GetPolicy(teamName string) (*domain.TeamPolicy, error) {
+	ctx := context.Background()
+	query := `
+		SELECT require_senior, no_two_juniors
+		FROM teams
+		WHERE name = $1
+	`
+	var policy domain.TeamPolicy
+	err := r.db.QueryRowContext(ctx, query, teamName).Scan(&policy.RequireSenior, &policy.NoTwoJuniors)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (r *teamRepo) SetPolicy(teamName string, policy *domain.TeamPolicy) error {
+	ctx := context.Background()
+	query := `
+		UPDATE teams
+		SET
+			require_senior = $1,
+			no_two_juniors = $2
+		WHERE name = $3
+	`
+	_, err := r.db.ExecContext(ctx, query, policy.RequireSenior, policy.NoTwoJuniors, teamName)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *teamRepo) AddHolidays(teamName string, holidays []*domain.Holiday) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO team_holidays (team_name, day, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_name, day) DO UPDATE SET name = EXCLUDED.name
+	`
+	for _, holiday := range holidays {
+		_, err := r.db.ExecContext(ctx, query, teamName, holiday.Date, holiday.Name)
+		if err != nil {
+			r.log.Errorf("failed to exec query: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetHolidays returns holidays of the teams starting from the given "2006-01-02" date.
+func (r *teamRepo) GetHolidays(teamNames []string, from string) ([]*domain.Holiday, error) {
+	ctx := context.Background()
+	query := `
+		SELECT team_name, to_char(day, 'YYYY-MM-DD'), name
+		FROM team_holidays
+		WHERE team_name = ANY($1) AND day >= $2
+		ORDER BY team_name, day
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(teamNames), from)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []*domain.Holiday{}
+	for rows.Next() {
+		var holiday domain.Holiday
+		err = rows.Scan(&holiday.TeamName, &holiday.Date, &holiday.Name)
+		if err != nil {
+			r.log.Errorf("failed to scan holiday: %v", err)
+			return nil, err
+		}
+		holidays = append(holidays, &holiday)
+	}
+
+	return holidays, nil
+}
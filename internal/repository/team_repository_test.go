@@ -22,7 +22,7 @@ func TestTeamRepo_Create(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputTeam := &domain.Team{Name: "Avengers"}
@@ -51,7 +51,7 @@ func TestTeamRepo_Create(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputTeam := &domain.Team{Name: "Avengers"}
@@ -81,7 +81,7 @@ func TestTeamRepo_GetByName(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		teamName := "Avengers"
@@ -118,7 +118,7 @@ func TestTeamRepo_GetByName(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		teamName := "Avengers"
@@ -149,7 +149,7 @@ func TestTeamRepo_CheckExist(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		teamName := "Avengers"
@@ -176,7 +176,7 @@ func TestTeamRepo_CheckExist(t *testing.T) {
 
 		repo := &teamRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		teamName := "NonExistentTeam"
@@ -195,3 +195,142 @@ func TestTeamRepo_CheckExist(t *testing.T) {
 		assert.Len(t, hook.AllEntries(), 0)
 	})
 }
+
+func TestTeamRepo_AddHolidays(t *testing.T) {
+	t.Run("upserts every holiday", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &teamRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		holidays := []*domain.Holiday{
+			{Date: "2025-01-01", Name: "New Year"},
+			{Date: "2025-01-07", Name: "Christmas"},
+		}
+
+		query := regexp.QuoteMeta(`
+			INSERT INTO team_holidays (team_name, day, name)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (team_name, day) DO UPDATE SET name = EXCLUDED.name
+		`)
+		mock.ExpectExec(query).WithArgs("backend", "2025-01-01", "New Year").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs("backend", "2025-01-07", "Christmas").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.AddHolidays("backend", holidays)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &teamRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectExec(`INSERT INTO team_holidays`).WillReturnError(errors.New("foreign key violation"))
+
+		err = repo.AddHolidays("ghost", []*domain.Holiday{{Date: "2025-01-01"}})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestTeamRepo_GetHolidays(t *testing.T) {
+	t.Run("returns holidays of the teams", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &teamRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"team_name", "day", "name"}).
+			AddRow("backend", "2025-01-01", "New Year").
+			AddRow("contractors", "2025-01-01", "New Year")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			SELECT team_name, to_char(day, 'YYYY-MM-DD'), name
+			FROM team_holidays
+			WHERE team_name = ANY($1) AND day >= $2
+		`)).WithArgs(`{"backend","contractors"}`, "2024-12-31").WillReturnRows(rows)
+
+		result, err := repo.GetHolidays([]string{"backend", "contractors"}, "2024-12-31")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.Holiday{
+			{TeamName: "backend", Date: "2025-01-01", Name: "New Year"},
+			{TeamName: "contractors", Date: "2025-01-01", Name: "New Year"},
+		}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
+
+func TestTeamRepo_GetPolicy(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &teamRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	rows := sqlmock.NewRows([]string{"require_senior", "no_two_juniors"}).AddRow(true, false)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT require_senior, no_two_juniors
+		FROM teams
+		WHERE name = $1
+	`)).WithArgs("backend").WillReturnRows(rows)
+
+	policy, err := repo.GetPolicy("backend")
+
+	assert.NoError(t, err)
+	assert.True(t, policy.RequireSenior)
+	assert.False(t, policy.NoTwoJuniors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestTeamRepo_SetPolicy(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &teamRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`
+		UPDATE teams
+		SET
+			require_senior = $1,
+			no_two_juniors = $2
+		WHERE name = $3
+	`)).WithArgs(true, true, "backend").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetPolicy("backend", &domain.TeamPolicy{RequireSenior: true, NoTwoJuniors: true})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+)
+
+type TelegramRepository interface {
+	Link(userID string, chatID int64) error
+	Unlink(chatID int64) (bool, error)
+	GetUserID(chatID int64) (string, bool, error)
+	GetChatID(userID string) (int64, bool, error)
+}
+
+type telegramRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewTelegramRepository(db DBTX, log *logger.Logger) TelegramRepository {
+	return &telegramRepo{db: db, log: log}
+}
+
+// Link points the user at the chat, replacing the chat they had linked before.
+func (r *telegramRepo) Link(userID string, chatID int64) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO telegram_links (user_id, chat_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET chat_id = EXCLUDED.chat_id, linked_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, chatID)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *telegramRepo) Unlink(chatID int64) (bool, error) {
+	ctx := context.Background()
+	query := `
+		DELETE FROM telegram_links
+		WHERE chat_id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, chatID)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		r.log.Errorf("failed to get affected rows: %v", err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *telegramRepo) GetUserID(chatID int64) (string, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT user_id
+		FROM telegram_links
+		WHERE chat_id = $1
+	`
+	var userID string
+	err := r.db.QueryRowContext(ctx, query, chatID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return "", false, err
+	}
+
+	return userID, true, nil
+}
+
+func (r *telegramRepo) GetChatID(userID string) (int64, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT chat_id
+		FROM telegram_links
+		WHERE user_id = $1
+	`
+	var chatID int64
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&chatID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return 0, false, err
+	}
+
+	return chatID, true, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"Pull-Requests-master/package/logger"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramRepo_Link(t *testing.T) {
+	t.Run("successful link", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &telegramRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO telegram_links (user_id, chat_id)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE
+		`)).WithArgs("u2", int64(123456789)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = repo.Link("u2", 123456789)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("chat linked to another user", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &telegramRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New(`duplicate key value violates unique constraint "telegram_links_chat_id_key"`)
+		mock.ExpectExec(`INSERT INTO telegram_links`).WillReturnError(expectedError)
+
+		err = repo.Link("u3", 123456789)
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestTelegramRepo_Unlink(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &telegramRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM telegram_links`)).
+		WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM telegram_links`)).
+		WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlinked, err := repo.Unlink(1)
+	assert.NoError(t, err)
+	assert.True(t, unlinked)
+
+	unlinked, err = repo.Unlink(2)
+	assert.NoError(t, err)
+	assert.False(t, unlinked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTelegramRepo_GetUserID(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &telegramRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM telegram_links
+		WHERE chat_id = $1
+	`)).WithArgs(int64(123456789)).WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("u2"))
+	mock.ExpectQuery(`FROM telegram_links`).WithArgs(int64(42)).WillReturnError(sql.ErrNoRows)
+
+	userID, found, err := repo.GetUserID(123456789)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "u2", userID)
+
+	_, found, err = repo.GetUserID(42)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestTelegramRepo_GetChatID(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &telegramRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM telegram_links
+		WHERE user_id = $1
+	`)).WithArgs("u2").WillReturnRows(sqlmock.NewRows([]string{"chat_id"}).AddRow(int64(123456789)))
+
+	chatID, found, err := repo.GetChatID("u2")
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(123456789), chatID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
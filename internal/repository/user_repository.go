@@ -5,6 +5,9 @@ import (
 	"Pull-Requests-master/package/logger"
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type UserRepository interface {
@@ -13,14 +16,20 @@ type UserRepository interface {
 	CheckExist(id string) (bool, error)
 	Create(user *domain.User) (*domain.User, error)
 	Update(user *domain.User) (*domain.User, error)
+	GetByID(id string) (*domain.User, error)
+	GetBySlackHandle(memberID string) (*domain.User, bool, error)
+	UpdateProfile(user *domain.User) (*domain.User, error)
+	GetAvailableTeammates(id string, now time.Time) ([]*domain.User, error)
+	GetAvailableOwners(names []string, now time.Time) ([]*domain.User, error)
+	GetAvailableMembers(teamNames []string, now time.Time) ([]*domain.User, error)
 }
 
 type userRepo struct {
-	db  *sql.DB
+	db  DBTX
 	log *logger.Logger
 }
 
-func NewUserRepository(db *sql.DB, log *logger.Logger) UserRepository {
+func NewUserRepository(db DBTX, log *logger.Logger) UserRepository {
 	return &userRepo{db: db, log: log}
 }
 
@@ -106,7 +115,7 @@ func (r *userRepo) GetReview(id string) ([]*domain.PullRequestShort, error) {
 		SELECT id, name, author_id, status
 		FROM pull_requests pr
 		JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-		WHERE pr_rev.user_id = $1
+		WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, id)
@@ -129,3 +138,172 @@ func (r *userRepo) GetReview(id string) ([]*domain.PullRequestShort, error) {
 
 	return pullRequests, nil
 }
+
+// userColumns is the full users row including the profile, read by scanUser.
+const userColumns = `id, username, is_active, team_name,
+			email, slack_handle, telegram_handle, time_zone, seniority, skills,
+			work_start, work_end, work_days`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.IsActive, &user.TeamName,
+		&user.Email, &user.SlackHandle, &user.TelegramHandle, &user.TimeZone, &user.Seniority, pq.Array(&user.Skills),
+		&user.WorkStart, &user.WorkEnd, pq.Array(&user.WorkDays),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) GetByID(id string) (*domain.User, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = $1
+	`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetBySlackHandle returns the user whose Slack handle is the member id. Only member ids
+// identify the user of a signed Slack request; names can be changed by anyone.
+func (r *userRepo) GetBySlackHandle(memberID string) (*domain.User, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE slack_handle <> '' AND slack_handle = $1
+		ORDER BY id
+		LIMIT 1
+	`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, memberID))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+func (r *userRepo) UpdateProfile(user *domain.User) (*domain.User, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE users
+		SET
+			email = $1,
+			slack_handle = $2,
+			telegram_handle = $3,
+			time_zone = $4,
+			seniority = $5,
+			skills = $6,
+			work_start = $7,
+			work_end = $8,
+			work_days = $9
+		WHERE id = $10
+		RETURNING ` + userColumns + `
+	`
+	updatedUser, err := scanUser(r.db.QueryRowContext(ctx, query,
+		user.Email, user.SlackHandle, user.TelegramHandle, user.TimeZone, user.Seniority, pq.Array(user.Skills),
+		user.WorkStart, user.WorkEnd, pq.Array(user.WorkDays), user.ID,
+	))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return updatedUser, nil
+}
+
+// GetAvailableTeammates returns active members of the user's team (the user included)
+// who are not inside an away window at the given moment.
+func (r *userRepo) GetAvailableTeammates(id string, now time.Time) ([]*domain.User, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name IN(
+			SELECT team_name
+			FROM users
+			WHERE id = $1
+		) AND is_active = TRUE
+		AND NOT EXISTS (
+			SELECT 1
+			FROM user_away_periods a
+			WHERE a.user_id = users.id AND a.starts_at <= $2 AND a.ends_at > $2
+		)
+		ORDER BY id
+	`
+	return r.queryUsers(ctx, query, id, now)
+}
+
+// GetAvailableOwners resolves CODEOWNERS owner names into active users who are not away:
+// a name is either a user id, a username or a team name.
+func (r *userRepo) GetAvailableOwners(names []string, now time.Time) ([]*domain.User, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE (id = ANY($1) OR username = ANY($1) OR team_name = ANY($1))
+		AND is_active = TRUE
+		AND NOT EXISTS (
+			SELECT 1
+			FROM user_away_periods a
+			WHERE a.user_id = users.id AND a.starts_at <= $2 AND a.ends_at > $2
+		)
+		ORDER BY id
+	`
+	return r.queryUsers(ctx, query, pq.Array(names), now)
+}
+
+// GetAvailableMembers returns active members of the teams who are not away at now.
+func (r *userRepo) GetAvailableMembers(teamNames []string, now time.Time) ([]*domain.User, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name = ANY($1) AND is_active = TRUE
+		AND NOT EXISTS (
+			SELECT 1
+			FROM user_away_periods a
+			WHERE a.user_id = users.id AND a.starts_at <= $2 AND a.ends_at > $2
+		)
+		ORDER BY id
+	`
+	return r.queryUsers(ctx, query, pq.Array(teamNames), now)
+}
+
+func (r *userRepo) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*domain.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			r.log.Errorf("failed scan: %v", err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
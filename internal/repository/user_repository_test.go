@@ -7,6 +7,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sirupsen/logrus"
@@ -24,7 +25,7 @@ func TestUserRepo_CheckExist(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "123"
@@ -48,7 +49,7 @@ func TestUserRepo_CheckExist(t *testing.T) {
 		require.NoError(t, err)
 		defer db.Close()
 
-		repo := &userRepo{db: db, log: &logger.Logger{log}}
+		repo := &userRepo{db: db, log: &logger.Logger{Logger: log}}
 		userID := "456"
 
 		rows := sqlmock.NewRows([]string{"exists"}).AddRow(false)
@@ -67,7 +68,7 @@ func TestUserRepo_CheckExist(t *testing.T) {
 		require.NoError(t, err)
 		defer db.Close()
 
-		repo := &userRepo{db: db, log: &logger.Logger{log}}
+		repo := &userRepo{db: db, log: &logger.Logger{Logger: log}}
 		userID := "789"
 
 		mock.ExpectQuery(`SELECT EXISTS`).WithArgs(userID).
@@ -86,7 +87,7 @@ func TestUserRepo_CheckExist(t *testing.T) {
 		require.NoError(t, err)
 		defer db.Close()
 
-		repo := &userRepo{db: db, log: &logger.Logger{log}}
+		repo := &userRepo{db: db, log: &logger.Logger{Logger: log}}
 		userID := "999"
 
 		rows := sqlmock.NewRows([]string{"exists"}).AddRow("not_a_boolean")
@@ -109,7 +110,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -146,7 +147,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -183,7 +184,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		// Test data
@@ -217,7 +218,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -265,7 +266,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -303,7 +304,7 @@ func TestUserRepo_Create(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log}, // ваш мок логгера
+			log: &logger.Logger{Logger: log}, // ваш мок логгера
 		}
 
 		inputUser := &domain.User{
@@ -339,7 +340,7 @@ func TestUserRepo_Update(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -391,7 +392,7 @@ func TestUserRepo_Update(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -436,7 +437,7 @@ func TestUserRepo_Update(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -480,7 +481,7 @@ func TestUserRepo_Update(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -522,7 +523,7 @@ func TestUserRepo_Update(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		inputUser := &domain.User{
@@ -568,7 +569,7 @@ func TestUserRepo_SetUserActive(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -612,7 +613,7 @@ func TestUserRepo_SetUserActive(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -656,7 +657,7 @@ func TestUserRepo_SetUserActive(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "non-existent-id"
@@ -690,7 +691,7 @@ func TestUserRepo_SetUserActive(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -727,7 +728,7 @@ func TestUserRepo_GetReview(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -755,7 +756,7 @@ func TestUserRepo_GetReview(t *testing.T) {
             SELECT id, name, author_id, status
             FROM pull_requests pr
             JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-            WHERE pr_rev.user_id = $1
+            WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
         `)).
 			WithArgs(userID).
 			WillReturnRows(rows)
@@ -776,7 +777,7 @@ func TestUserRepo_GetReview(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-with-no-reviews"
@@ -787,7 +788,7 @@ func TestUserRepo_GetReview(t *testing.T) {
             SELECT id, name, author_id, status
             FROM pull_requests pr
             JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-            WHERE pr_rev.user_id = $1
+            WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
         `)).
 			WithArgs(userID).
 			WillReturnRows(rows)
@@ -809,7 +810,7 @@ func TestUserRepo_GetReview(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -819,7 +820,7 @@ func TestUserRepo_GetReview(t *testing.T) {
             SELECT id, name, author_id, status
             FROM pull_requests pr
             JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-            WHERE pr_rev.user_id = $1
+            WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
         `)).
 			WithArgs(userID).
 			WillReturnError(expectedError)
@@ -842,7 +843,7 @@ func TestUserRepo_GetReview(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -855,7 +856,7 @@ func TestUserRepo_GetReview(t *testing.T) {
             SELECT id, name, author_id, status
             FROM pull_requests pr
             JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-            WHERE pr_rev.user_id = $1
+            WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
         `)).
 			WithArgs(userID).
 			WillReturnRows(rows)
@@ -877,7 +878,7 @@ func TestUserRepo_GetReview(t *testing.T) {
 
 		repo := &userRepo{
 			db:  db,
-			log: &logger.Logger{log},
+			log: &logger.Logger{Logger: log},
 		}
 
 		userID := "user-123"
@@ -891,7 +892,7 @@ func TestUserRepo_GetReview(t *testing.T) {
             SELECT id, name, author_id, status
             FROM pull_requests pr
             JOIN pr_reviewrs pr_rev ON pr_rev.pr_id = pr.id
-            WHERE pr_rev.user_id = $1
+            WHERE pr_rev.user_id = $1 AND pr_rev.unassigned_at IS NULL AND pr_rev.assigned = TRUE
         `)).
 			WithArgs(userID).
 			WillReturnRows(rows)
@@ -904,3 +905,324 @@ func TestUserRepo_GetReview(t *testing.T) {
 		assert.Len(t, hook.AllEntries(), 0)
 	})
 }
+
+func TestUserRepo_GetByID(t *testing.T) {
+	t.Run("successfully get user with profile", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+			"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+			"work_start", "work_end", "work_days"}).
+			AddRow("user-1", "alice", true, "backend",
+				"alice@example.com", "alice", "alice_tg", "Europe/Moscow", "senior", "{go,db}",
+				"10:00", "19:00", "{1,2,3,4,5}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			SELECT id, username, is_active, team_name,
+				email, slack_handle, telegram_handle, time_zone, seniority, skills,
+				work_start, work_end, work_days
+			FROM users
+			WHERE id = $1
+		`)).WithArgs("user-1").WillReturnRows(rows)
+
+		result, err := repo.GetByID("user-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.User{
+			Member:   domain.Member{ID: "user-1", Username: "alice", IsActive: true},
+			TeamName: "backend",
+			Profile: domain.Profile{
+				Email:          "alice@example.com",
+				SlackHandle:    "alice",
+				TelegramHandle: "alice_tg",
+				TimeZone:       "Europe/Moscow",
+				Seniority:      "senior",
+				Skills:         []string{"go", "db"},
+				WorkStart:      "10:00",
+				WorkEnd:        "19:00",
+				WorkDays:       []int64{1, 2, 3, 4, 5},
+			},
+		}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`SELECT id, username, is_active, team_name`).
+			WithArgs("ghost").WillReturnError(sql.ErrNoRows)
+
+		result, err := repo.GetByID("ghost")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Contains(t, hook.LastEntry().Message, "failed to exec query")
+	})
+}
+
+func TestUserRepo_GetBySlackHandle(t *testing.T) {
+	t.Run("found by member id", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+			"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+			"work_start", "work_end", "work_days"}).
+			AddRow("user-1", "alice", true, "backend",
+				"", "U024BE7LH", "", "", "", "{}",
+				"", "", "{}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			FROM users
+			WHERE slack_handle <> '' AND slack_handle = $1
+		`)).WithArgs("U024BE7LH").WillReturnRows(rows)
+
+		result, found, err := repo.GetBySlackHandle("U024BE7LH")
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "user-1", result.ID)
+		assert.Equal(t, "U024BE7LH", result.SlackHandle)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`FROM users`).WillReturnError(sql.ErrNoRows)
+
+		result, found, err := repo.GetBySlackHandle("U0000000")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
+
+func TestUserRepo_UpdateProfile(t *testing.T) {
+	t.Run("successful profile update", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		inputUser := &domain.User{
+			Member:   domain.Member{ID: "user-1", Username: "alice", IsActive: true},
+			TeamName: "backend",
+			Profile: domain.Profile{
+				Email:     "alice@example.com",
+				TimeZone:  "Asia/Novosibirsk",
+				Seniority: "middle",
+				Skills:    []string{"frontend"},
+				WorkStart: "08:00",
+				WorkEnd:   "17:00",
+				WorkDays:  []int64{1, 2, 3, 4},
+			},
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+			"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+			"work_start", "work_end", "work_days"}).
+			AddRow("user-1", "alice", true, "backend",
+				"alice@example.com", "", "", "Asia/Novosibirsk", "middle", "{frontend}",
+				"08:00", "17:00", "{1,2,3,4}")
+		mock.ExpectQuery(`UPDATE users`).
+			WithArgs("alice@example.com", "", "", "Asia/Novosibirsk", "middle", `{"frontend"}`,
+				"08:00", "17:00", "{1,2,3,4}", "user-1").
+			WillReturnRows(rows)
+
+		result, err := repo.UpdateProfile(inputUser)
+
+		assert.NoError(t, err)
+		assert.Equal(t, inputUser, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`UPDATE users`).WillReturnError(expectedError)
+
+		result, err := repo.UpdateProfile(&domain.User{Member: domain.Member{ID: "user-1"}, Profile: domain.Profile{Skills: []string{}}})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestUserRepo_GetAvailableTeammates(t *testing.T) {
+	t.Run("returns active teammates outside away windows", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 11, 3, 15, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+			"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+			"work_start", "work_end", "work_days"}).
+			AddRow("u1", "alice", true, "backend", "", "", "", "Europe/Moscow", "", "{}", "", "", "{}").
+			AddRow("u2", "bob", true, "backend", "", "", "", "Asia/Novosibirsk", "", "{}", "09:00", "18:00", "{}")
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			FROM users
+			WHERE team_name IN(
+				SELECT team_name
+				FROM users
+				WHERE id = $1
+			) AND is_active = TRUE
+			AND NOT EXISTS (
+				SELECT 1
+				FROM user_away_periods a
+				WHERE a.user_id = users.id AND a.starts_at <= $2 AND a.ends_at > $2
+			)
+		`)).WithArgs("u1", now).WillReturnRows(rows)
+
+		result, err := repo.GetAvailableTeammates("u1", now)
+
+		assert.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "Asia/Novosibirsk", result[1].TimeZone)
+		assert.Equal(t, "09:00", result[1].WorkStart)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &userRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("connection refused")
+		mock.ExpectQuery(`FROM users`).WillReturnError(expectedError)
+
+		result, err := repo.GetAvailableTeammates("u1", time.Now())
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestUserRepo_GetAvailableOwners(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &userRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Date(2025, 11, 3, 15, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+		"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+		"work_start", "work_end", "work_days"}).
+		AddRow("u3", "carol", true, "dba", "", "", "", "", "", "{}", "", "", "{}")
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		WHERE (id = ANY($1) OR username = ANY($1) OR team_name = ANY($1))
+		AND is_active = TRUE
+	`)).WithArgs(`{"dba","alice"}`, now).WillReturnRows(rows)
+
+	result, err := repo.GetAvailableOwners([]string{"dba", "alice"}, now)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "dba", result[0].TeamName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestUserRepo_GetAvailableMembers(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &userRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Date(2025, 11, 3, 15, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "username", "is_active", "team_name",
+		"email", "slack_handle", "telegram_handle", "time_zone", "seniority", "skills",
+		"work_start", "work_end", "work_days"}).
+		AddRow("u1", "alice", true, "backend", "", "", "", "", "", "{}", "", "", "{}").
+		AddRow("u3", "carol", true, "dba", "", "", "", "", "", "{}", "", "", "{}")
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		WHERE team_name = ANY($1) AND is_active = TRUE
+	`)).WithArgs(`{"backend","dba"}`, now).WillReturnRows(rows)
+
+	result, err := repo.GetAvailableMembers([]string{"backend", "dba"}, now)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "dba", result[1].TeamName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
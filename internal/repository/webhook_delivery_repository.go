@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type WebhookDeliveryRepository interface {
+	Enqueue(delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error)
+	Get(id int64) (*domain.WebhookDelivery, bool, error)
+	GetDue(now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	GetBySubscription(subscriptionID int64, status string, limit int) ([]*domain.WebhookDelivery, error)
+	MarkDone(id int64, attempts int, responseStatus int) error
+	Reschedule(id int64, attempts int, next time.Time, responseStatus int, lastError string) error
+	MarkFailed(id int64, attempts int, responseStatus int, lastError string) error
+	Replay(id int64, now time.Time) (*domain.WebhookDelivery, error)
+}
+
+type webhookDeliveryRepo struct {
+	db  DBTX
+	log *logger.Logger
+}
+
+func NewWebhookDeliveryRepository(db DBTX, log *logger.Logger) WebhookDeliveryRepository {
+	return &webhookDeliveryRepo{db: db, log: log}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event, payload, status, attempts, next_attempt_at,
+			response_status, last_error, created_at`
+
+func scanWebhookDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload []byte
+	err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.Event, &payload, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &delivery.ResponseStatus, &delivery.LastError, &delivery.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepo) Enqueue(delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event, payload)
+		VALUES ($1, $2, $3)
+		RETURNING ` + webhookDeliveryColumns + `
+	`
+	newDelivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, delivery.SubscriptionID, delivery.Event,
+		[]byte(delivery.Payload)))
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return newDelivery, nil
+}
+
+func (r *webhookDeliveryRepo) Get(id int64) (*domain.WebhookDelivery, bool, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, false, err
+	}
+
+	return delivery, true, nil
+}
+
+// GetDue returns the pending deliveries whose next attempt is due, oldest first.
+func (r *webhookDeliveryRepo) GetDue(now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2
+	`
+	return r.query(ctx, query, now, limit)
+}
+
+// GetBySubscription is the delivery log of a subscription, newest first; an empty status
+// returns all of them.
+func (r *webhookDeliveryRepo) GetBySubscription(subscriptionID int64, status string, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+	return r.query(ctx, query, subscriptionID, status, limit)
+}
+
+func (r *webhookDeliveryRepo) MarkDone(id int64, attempts int, responseStatus int) error {
+	ctx := context.Background()
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'done', attempts = $2, response_status = $3, last_error = ''
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts, responseStatus)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepo) Reschedule(id int64, attempts int, next time.Time, responseStatus int, lastError string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = $2, next_attempt_at = $3, response_status = $4, last_error = $5
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts, next, responseStatus, lastError)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepo) MarkFailed(id int64, attempts int, responseStatus int, lastError string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'failed', attempts = $2, response_status = $3, last_error = $4
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, attempts, responseStatus, lastError)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Replay puts a failed delivery back in the queue with fresh attempts. It returns nil if
+// the delivery isn't failed.
+func (r *webhookDeliveryRepo) Replay(id int64, now time.Time) (*domain.WebhookDelivery, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = $2
+		WHERE id = $1 AND status = 'failed'
+		RETURNING ` + webhookDeliveryColumns + `
+	`
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id, now))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookDeliveryRepo) query(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Errorf("failed to exec query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			r.log.Errorf("failed to scan webhook delivery: %v", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
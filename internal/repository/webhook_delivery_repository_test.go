@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/package/logger"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var webhookDeliveryRowColumns = []string{"id", "subscription_id", "event", "payload", "status", "attempts",
+	"next_attempt_at", "response_status", "last_error", "created_at"}
+
+func TestWebhookDeliveryRepo_Enqueue(t *testing.T) {
+	t.Run("successful enqueue", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &webhookDeliveryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+		payload := []byte(`{"event":"pr.merged"}`)
+		rows := sqlmock.NewRows(webhookDeliveryRowColumns).
+			AddRow(7, 1, "pr.merged", payload, "pending", 0, now, 0, "", now)
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			INSERT INTO webhook_deliveries (subscription_id, event, payload)
+			VALUES ($1, $2, $3)
+		`)).WithArgs(int64(1), "pr.merged", payload).WillReturnRows(rows)
+
+		result, err := repo.Enqueue(&domain.WebhookDelivery{
+			SubscriptionID: 1,
+			Event:          domain.EventPRMerged,
+			Payload:        payload,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), result.ID)
+		assert.Equal(t, domain.JobPending, result.Status)
+		assert.JSONEq(t, string(payload), string(result.Payload))
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &webhookDeliveryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		expectedError := errors.New("foreign key violation")
+		mock.ExpectQuery(`INSERT INTO webhook_deliveries`).WillReturnError(expectedError)
+
+		result, err := repo.Enqueue(&domain.WebhookDelivery{SubscriptionID: 9, Payload: []byte(`{}`)})
+
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, hook.AllEntries(), 1)
+	})
+}
+
+func TestWebhookDeliveryRepo_GetBySubscription(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows(webhookDeliveryRowColumns).
+		AddRow(9, 1, "pr.created", []byte(`{}`), "failed", 10, now, 503, "POST https://a: 503", now).
+		AddRow(3, 1, "pr.created", []byte(`{}`), "failed", 10, now, 0, "connection refused", now)
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`)).WithArgs(int64(1), "failed", 100).WillReturnRows(rows)
+
+	result, err := repo.GetBySubscription(1, domain.JobFailed, 100)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 503, result[0].ResponseStatus)
+	assert.Equal(t, "connection refused", result[1].LastError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestWebhookDeliveryRepo_Reschedule(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepo{
+		db:  db,
+		log: &logger.Logger{Logger: log},
+	}
+
+	next := time.Date(2025, 9, 1, 10, 4, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`
+		UPDATE webhook_deliveries
+		SET attempts = $2, next_attempt_at = $3, response_status = $4, last_error = $5
+		WHERE id = $1
+	`)).WithArgs(int64(7), 3, next, 502, "bad gateway").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Reschedule(7, 3, next, 502, "bad gateway")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, hook.AllEntries(), 0)
+}
+
+func TestWebhookDeliveryRepo_Replay(t *testing.T) {
+	t.Run("failed delivery is queued again", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &webhookDeliveryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		now := time.Date(2025, 9, 2, 8, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(webhookDeliveryRowColumns).
+			AddRow(7, 1, "pr.merged", []byte(`{}`), "pending", 0, now, 503, "POST https://a: 503", now)
+		mock.ExpectQuery(regexp.QuoteMeta(`
+			UPDATE webhook_deliveries
+			SET status = 'pending', attempts = 0, next_attempt_at = $2
+			WHERE id = $1 AND status = 'failed'
+		`)).WithArgs(int64(7), now).WillReturnRows(rows)
+
+		result, err := repo.Replay(7, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobPending, result.Status)
+		assert.Equal(t, 0, result.Attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+
+	t.Run("delivery not failed", func(t *testing.T) {
+		log, hook := test.NewNullLogger()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := &webhookDeliveryRepo{
+			db:  db,
+			log: &logger.Logger{Logger: log},
+		}
+
+		mock.ExpectQuery(`UPDATE webhook_deliveries`).WillReturnError(sql.ErrNoRows)
+
+		result, err := repo.Replay(7, time.Now())
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, hook.AllEntries(), 0)
+	})
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"Pull-Requests-master/internal/service"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+)
+
+// Relay publishes the outbox of the service, more often than the scheduler ticks.
+type Relay struct {
+	s        *service.Service
+	log      *logger.Logger
+	interval time.Duration
+}
+
+func NewRelay(s *service.Service, log *logger.Logger, interval time.Duration) *Relay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Relay{
+		s:        s,
+		log:      log,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.drain(ctx)
+		}
+	}
+}
+
+// drain publishes batch after batch until the outbox is empty or publishing fails.
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.s.RelayOutbox()
+		if err != nil {
+			r.log.Errorf("failed to relay outbox: %v", err)
+			return
+		}
+		if published == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+)
+
+// Retrier delivers one queue of calls to the outside world: right after the service
+// queues new ones and every interval for the retries. It runs whenever the queue is in
+// use, whether the scheduler is enabled or not.
+type Retrier struct {
+	name     string
+	process  func(now time.Time) error
+	queued   <-chan struct{}
+	log      *logger.Logger
+	interval time.Duration
+}
+
+// NewRetrier runs process, e.g. ProcessCodeHostJobs of the service, whenever queued is
+// signalled or the interval passes.
+func NewRetrier(name string, process func(now time.Time) error, queued <-chan struct{}, log *logger.Logger, interval time.Duration) *Retrier {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Retrier{
+		name:     name,
+		process:  process,
+		queued:   queued,
+		log:      log,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (r *Retrier) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.retry()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.queued:
+			r.retry()
+		case <-ticker.C:
+			r.retry()
+		}
+	}
+}
+
+func (r *Retrier) retry() {
+	if err := r.process(time.Now()); err != nil {
+		r.log.Errorf("failed to process %s: %v", r.name, err)
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"Pull-Requests-master/internal/service"
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+)
+
+// Scheduler periodically runs background jobs of the service.
+type Scheduler struct {
+	s        *service.Service
+	log      *logger.Logger
+	interval time.Duration
+	reassign bool
+}
+
+func New(s *service.Service, log *logger.Logger, interval time.Duration, reassign bool) *Scheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Scheduler{
+		s:        s,
+		log:      log,
+		interval: interval,
+		reassign: reassign,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (sch *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(sch.interval)
+	defer ticker.Stop()
+
+	sch.tick()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sch.tick()
+		}
+	}
+}
+
+func (sch *Scheduler) tick() {
+	if err := sch.s.ProcessAwayPeriods(time.Now(), sch.reassign); err != nil {
+		sch.log.Errorf("failed to process away periods: %v", err)
+	}
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/assignment"
+	"Pull-Requests-master/internal/codeowners"
+	"Pull-Requests-master/internal/domain"
+	"strings"
+	"time"
+)
+
+// reviewerPlan is who the assignment puts on a new pull request and why.
+type reviewerPlan struct {
+	strategy assignment.Strategy
+	required []*domain.RequiredReviewer
+	// source is the rule the regular candidates come from.
+	source string
+	info   *domain.AssignmentInfo
+	// pool are the regular candidates left after the exclusion rules.
+	pool      []*domain.User
+	reviewers []*domain.User
+}
+
+// planReviewers picks the mandatory and the regular reviewers of a new pull request
+// without assigning them.
+func (s *Service) planReviewers(pr *domain.PullRequest, author *domain.User, repo *domain.Repository) (*reviewerPlan, error) {
+	plan := &reviewerPlan{strategy: s.strategy(repo)}
+	ex, err := s.exclusions()
+	if err != nil {
+		return nil, err
+	}
+	required, excludedRequired, err := s.requiredReviewers(pr, author.TeamName, plan.strategy, ex)
+	if err != nil {
+		return nil, err
+	}
+	plan.required = required
+	taken := []string{}
+	for _, reviewer := range required {
+		taken = append(taken, reviewer.UserID)
+	}
+
+	// Mandatory reviewers come on top of the regular ones.
+	candidates, source, info, err := s.candidates(pr, repo)
+	if err != nil {
+		return nil, err
+	}
+	candidates = assignment.Exclude(candidates, taken...)
+	pool := len(candidates)
+	candidates, excluded := ex.Filter(candidates, authors(pr), taken)
+	info = reportExclusions(info, excludedRequired, false)
+	info = reportExclusions(info, excluded, pool > 0 && len(candidates) == 0)
+	plan.source = source
+	plan.info = info
+	plan.pool = candidates
+
+	rule, err := s.seniorityRule(author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.reviewers(taken)
+	if err != nil {
+		return nil, err
+	}
+
+	pairings, err := s.pairings(pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.reviewers, err = s.selectReviewers(plan.strategy, assignment.Request{
+		Candidates: candidates,
+		Count:      reviewerCount(repo),
+		Labels:     pr.Labels,
+		Existing:   existing,
+		Seniority:  rule,
+		Exclusions: ex,
+		Pairings:   pairings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// candidates returns who may review the pull request and the rule they come from: the
+// owners of its changed files when the repository has an ownership file, otherwise the
+// teams of the registered repository or, failing that, the author's team. The authors are
+// never candidates.
+func (s *Service) candidates(pr *domain.PullRequest, repo *domain.Repository) ([]*domain.User, string, *domain.AssignmentInfo, error) {
+	now := s.cfg.Clock.Now()
+	var info *domain.AssignmentInfo
+
+	matches, owners, err := s.owners(pr)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if len(matches) > 0 {
+		info = &domain.AssignmentInfo{Ownership: matches}
+	}
+
+	if len(owners) > 0 {
+		candidates, err := s.userRepo.GetAvailableOwners(owners, now)
+		if err != nil {
+			s.log.Errorf("failed to get owners: %v", err)
+			return nil, "", nil, err
+		}
+		candidates = assignment.Exclude(candidates, authors(pr)...)
+		if len(candidates) > 0 {
+			return candidates, domain.RuleCodeOwners, info, nil
+		}
+	}
+	if info != nil {
+		s.log.Debugf("no available owners for pr %s, falling back to the team", pr.ID)
+		info.FallbackToTeam = true
+	}
+
+	if repo != nil && len(repo.Teams) > 0 {
+		candidates, err := s.userRepo.GetAvailableMembers(repo.Teams, now)
+		if err != nil {
+			s.log.Errorf("failed to get candidates: %v", err)
+			return nil, "", nil, err
+		}
+		return assignment.Exclude(candidates, authors(pr)...), domain.RuleRepositoryTeams, info, nil
+	}
+
+	candidates, err := s.userRepo.GetAvailableTeammates(pr.AuthorID, now)
+	if err != nil {
+		s.log.Errorf("failed to get candidates: %v", err)
+		return nil, "", nil, err
+	}
+	return assignment.Exclude(candidates, authors(pr)...), domain.RuleAuthorTeam, info, nil
+}
+
+// requiredReviewers picks members of the mandatory groups that apply to the pull request.
+// A group applies when it is scoped to the author's team and the repository (or left open)
+// and has no labels or shares one with the pull request. It also returns the members the
+// exclusion rules kept out.
+func (s *Service) requiredReviewers(pr *domain.PullRequest, teamName string, strategy assignment.Strategy,
+	ex assignment.Exclusions) ([]*domain.RequiredReviewer, []*domain.ExcludedCandidate, error) {
+	groups, err := s.groupRepo.GetApplicable(teamName, pr.Repository)
+	if err != nil {
+		s.log.Errorf("failed to get reviewer groups: %v", err)
+		return nil, nil, err
+	}
+
+	pairings, err := s.pairings(pr.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	required := []*domain.RequiredReviewer{}
+	excluded := []*domain.ExcludedCandidate{}
+	taken := []string{}
+	for _, group := range groups {
+		if len(group.Labels) > 0 && !sharesLabel(group.Labels, pr.Labels) {
+			continue
+		}
+
+		candidates, err := s.groupMembers(group, append(authors(pr), taken...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		candidates, out := ex.Filter(candidates, authors(pr), taken)
+		excluded = append(excluded, out...)
+
+		picked, err := s.selectReviewers(strategy, assignment.Request{
+			Candidates: candidates,
+			Count:      group.Count,
+			Exclusions: ex,
+			Pairings:   pairings,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(picked) < group.Count {
+			s.log.Debugf("reviewer group %s has %d of %d reviewers available for pr %s",
+				group.Name, len(picked), group.Count, pr.ID)
+		}
+
+		for _, user := range picked {
+			required = append(required, &domain.RequiredReviewer{UserID: user.ID, Group: group.Name})
+			taken = append(taken, user.ID)
+		}
+	}
+	return required, excluded, nil
+}
+
+// authors are the author and the co-authors of the pull request.
+func authors(pr *domain.PullRequest) []string {
+	return append([]string{pr.AuthorID}, pr.CoAuthorIDs...)
+}
+
+func sharesLabel(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupMembers returns the available members of the group, skipping the excluded ids.
+func (s *Service) groupMembers(group *domain.ReviewerGroup, excluded ...string) ([]*domain.User, error) {
+	users, err := s.userRepo.GetAvailableOwners(group.Members, s.cfg.Clock.Now())
+	if err != nil {
+		s.log.Errorf("failed to get members of reviewer group: %v", err)
+		return nil, err
+	}
+	return assignment.Exclude(users, excluded...), nil
+}
+
+// owners matches the changed files against the repository's ownership file.
+func (s *Service) owners(pr *domain.PullRequest) ([]*domain.OwnershipMatch, []string, error) {
+	if pr.Repository == "" || len(pr.ChangedFiles) == 0 {
+		return nil, nil, nil
+	}
+
+	content, found, err := s.ownersRepo.Get(pr.Repository)
+	if err != nil {
+		s.log.Errorf("failed to get code owners: %v", err)
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil
+	}
+
+	file, err := codeowners.Parse(content)
+	if err != nil {
+		// Files are validated on upload, so this only happens if the parser got stricter.
+		s.log.Errorf("failed to parse code owners of %s: %v", pr.Repository, err)
+		return nil, nil, nil
+	}
+
+	matches := []*domain.OwnershipMatch{}
+	owners := []string{}
+	seen := map[string]bool{}
+	for _, path := range pr.ChangedFiles {
+		rule := file.Match(path)
+		if rule == nil {
+			continue
+		}
+		matches = append(matches, &domain.OwnershipMatch{
+			Path:   path,
+			Rule:   rule.Pattern,
+			Line:   rule.Line,
+			Owners: rule.Owners,
+		})
+		for _, owner := range rule.Owners {
+			name := codeowners.OwnerName(owner)
+			if !seen[name] {
+				seen[name] = true
+				owners = append(owners, name)
+			}
+		}
+	}
+	return matches, owners, nil
+}
+
+// ruleDetail names the ownership rules that made the user a candidate, matching them by
+// id, username or team. Other rules have no detail.
+func ruleDetail(rule string, info *domain.AssignmentInfo, user *domain.User) string {
+	if rule != domain.RuleCodeOwners || info == nil {
+		return ""
+	}
+	patterns := []string{}
+	seen := map[string]bool{}
+	for _, match := range info.Ownership {
+		for _, owner := range match.Owners {
+			name := codeowners.OwnerName(owner)
+			if name != user.ID && name != user.Username && name != user.TeamName {
+				continue
+			}
+			if !seen[match.Rule] {
+				seen[match.Rule] = true
+				patterns = append(patterns, match.Rule)
+			}
+			break
+		}
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// recordAssignment stores why the reviewer got on the pull request.
+func (s *Service) recordAssignment(record *domain.AssignmentRecord) error {
+	err := s.assignmentRepo.Add(record)
+	if err != nil {
+		s.log.Errorf("failed to record assignment: %v", err)
+		return err
+	}
+	return nil
+}
+
+// selectReviewers runs the strategy on the request, filling in the time and the holidays
+// of the candidates' teams.
+func (s *Service) selectReviewers(strategy assignment.Strategy, req assignment.Request) ([]*domain.User, error) {
+	req.Now = s.cfg.Clock.Now()
+
+	teams := []string{}
+	seen := map[string]bool{}
+	for _, c := range req.Candidates {
+		if !seen[c.TeamName] {
+			seen[c.TeamName] = true
+			teams = append(teams, c.TeamName)
+		}
+	}
+
+	holidays, err := s.loadHolidays(teams, req.Now)
+	if err != nil {
+		return nil, err
+	}
+	req.Holidays = holidays
+
+	return strategy.Select(req), nil
+}
+
+func (s *Service) exclusions() (assignment.Exclusions, error) {
+	rules, err := s.exclusionRepo.GetAll()
+	if err != nil {
+		s.log.Errorf("failed to get exclusion rules: %v", err)
+		return assignment.Exclusions{}, err
+	}
+	return assignment.NewExclusions(rules), nil
+}
+
+// pairings counts how often each reviewer reviewed the author within the pairing window.
+func (s *Service) pairings(authorID string) (map[string]int, error) {
+	since := s.cfg.Clock.Now().Add(-s.cfg.PairingWindow)
+	counts, err := s.prRepo.GetPairCounts(authorID, since)
+	if err != nil {
+		s.log.Errorf("failed to get pair counts: %v", err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+// reportExclusions adds the excluded candidates to the assignment info; none tells whether
+// they were all the candidates there were.
+func reportExclusions(info *domain.AssignmentInfo, excluded []*domain.ExcludedCandidate, none bool) *domain.AssignmentInfo {
+	if len(excluded) == 0 {
+		return info
+	}
+	if info == nil {
+		info = &domain.AssignmentInfo{}
+	}
+	info.Excluded = append(info.Excluded, excluded...)
+	info.AllExcluded = info.AllExcluded || none
+	return info
+}
+
+// seniorityRule reads the reviewer mix the team asks for.
+func (s *Service) seniorityRule(teamName string) (assignment.SeniorityRule, error) {
+	policy, err := s.teamRepo.GetPolicy(teamName)
+	if err != nil {
+		s.log.Errorf("failed to get team policy: %v", err)
+		return assignment.SeniorityRule{}, err
+	}
+	return assignment.SeniorityRule{
+		RequireSenior: policy.RequireSenior,
+		NoTwoJuniors:  policy.NoTwoJuniors,
+	}, nil
+}
+
+// reviewers loads the users behind the reviewer ids, skipping the excluded ones.
+func (s *Service) reviewers(ids []string, excluded ...string) ([]*domain.User, error) {
+	skip := map[string]bool{}
+	for _, id := range excluded {
+		skip[id] = true
+	}
+
+	users := []*domain.User{}
+	for _, id := range ids {
+		if skip[id] {
+			continue
+		}
+		user, err := s.userRepo.GetByID(id)
+		if err != nil {
+			s.log.Errorf("failed to get user by id: %v", err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// reviewDueAt counts the review SLA in working time of the team, skipping its holidays.
+func (s *Service) reviewDueAt(teamName string) (*time.Time, error) {
+	if s.cfg.ReviewSLA <= 0 {
+		return nil, nil
+	}
+	now := s.cfg.Clock.Now()
+
+	holidays, err := s.loadHolidays([]string{teamName}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	dueAt := s.cfg.Hours.Add(now, s.cfg.ReviewSLA, holidays.DayOff(teamName))
+	if dueAt.IsZero() {
+		s.log.Debugf("no working time left to count review sla of team %s", teamName)
+		return nil, nil
+	}
+	return &dueAt, nil
+}
+
+func (s *Service) loadHolidays(teams []string, now time.Time) (assignment.Holidays, error) {
+	if len(teams) == 0 {
+		return assignment.Holidays{}, nil
+	}
+
+	// Start a day earlier: the local date may still be yesterday somewhere.
+	from := now.AddDate(0, 0, -1).Format("2006-01-02")
+	holidays, err := s.teamRepo.GetHolidays(teams, from)
+	if err != nil {
+		s.log.Errorf("failed to get holidays: %v", err)
+		return nil, err
+	}
+
+	return assignment.NewHolidays(holidays), nil
+}
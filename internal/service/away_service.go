@@ -0,0 +1,194 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"time"
+)
+
+func (s *Service) AddAwayPeriod(period *domain.AwayPeriod) (*domain.AwayPeriod, error) {
+	exists, err := s.userRepo.CheckExist(period.UserID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of user: %v", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Debugf("user with id: %s not found", period.UserID)
+		return nil, errors.ErrNotFound
+	}
+
+	newPeriod, err := s.awayRepo.Create(period)
+	if err != nil {
+		s.log.Errorf("failed to create away period: %v", err)
+		return nil, err
+	}
+
+	return newPeriod, nil
+}
+
+func (s *Service) GetAwayPeriods(userID string) ([]*domain.AwayPeriod, error) {
+	exists, err := s.userRepo.CheckExist(userID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of user: %v", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Debugf("user with id: %s not found", userID)
+		return nil, errors.ErrNotFound
+	}
+
+	periods, err := s.awayRepo.GetByUser(userID)
+	if err != nil {
+		s.log.Errorf("failed to get away periods: %v", err)
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// DeleteAwayPeriod switches the user back on when the period being deleted switched them
+// off, as if it had finished.
+func (s *Service) DeleteAwayPeriod(id int64) error {
+	return s.inTx(func(tx *Service) error {
+		period, found, err := tx.awayRepo.Delete(id)
+		if err != nil {
+			tx.log.Errorf("failed to delete away period: %v", err)
+			return err
+		}
+		if !found {
+			tx.log.Debugf("away period with id: %d not found", id)
+			return errors.ErrNotFound
+		}
+
+		if period.Deactivated {
+			return tx.releaseUser(period.UserID, tx.cfg.Clock.Now())
+		}
+		return nil
+	})
+}
+
+// ProcessAwayPeriods switches users off when their away window opens, optionally handing their
+// open reviews over to teammates, and switches them back on when the window closes. A period
+// that fails is logged and left for the next run.
+func (s *Service) ProcessAwayPeriods(now time.Time, reassign bool) error {
+	starting, err := s.awayRepo.GetStarting(now)
+	if err != nil {
+		s.log.Errorf("failed to get starting away periods: %v", err)
+		return err
+	}
+
+	for _, period := range starting {
+		if err := s.startAway(period, reassign); err != nil {
+			s.log.Errorf("failed to start away period %d of user %s: %v", period.ID, period.UserID, err)
+			continue
+		}
+		s.log.Infof("away period %d of user %s started", period.ID, period.UserID)
+	}
+
+	ending, err := s.awayRepo.GetEnding(now)
+	if err != nil {
+		s.log.Errorf("failed to get ending away periods: %v", err)
+		return err
+	}
+
+	for _, period := range ending {
+		if err := s.finishAway(period, now); err != nil {
+			s.log.Errorf("failed to finish away period %d of user %s: %v", period.ID, period.UserID, err)
+			continue
+		}
+		s.log.Infof("away period %d of user %s finished", period.ID, period.UserID)
+	}
+
+	return nil
+}
+
+// startAway switches the user off and marks the period started in one transaction, so the
+// period always knows whether it has to switch the user back on. Open reviews are handed
+// over afterwards; one that can't be is left with the user.
+func (s *Service) startAway(period *domain.AwayPeriod, reassign bool) error {
+	var userID string
+	err := s.inTx(func(tx *Service) error {
+		user, err := tx.userRepo.GetByID(period.UserID)
+		if err != nil {
+			tx.log.Errorf("failed to get user by id: %v", err)
+			return err
+		}
+		userID = user.ID
+
+		deactivated := false
+		if user.IsActive {
+			if _, err := tx.SetUserActive(user.ID, false); err != nil {
+				return err
+			}
+			deactivated = true
+		}
+
+		if err := tx.awayRepo.MarkStarted(period.ID, deactivated); err != nil {
+			tx.log.Errorf("failed to mark away period as started: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if reassign {
+		s.reassignOpenReviews(userID)
+	}
+	return nil
+}
+
+func (s *Service) finishAway(period *domain.AwayPeriod, now time.Time) error {
+	return s.inTx(func(tx *Service) error {
+		if period.Deactivated {
+			if err := tx.releaseUser(period.UserID, now); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.awayRepo.MarkFinished(period.ID); err != nil {
+			tx.log.Errorf("failed to mark away period as finished: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// releaseUser switches a user the scheduler switched off back on, unless another of their
+// windows is still open; that window then switches them on when it closes.
+func (s *Service) releaseUser(userID string, now time.Time) error {
+	passed, err := s.awayRepo.PassDeactivated(userID, now)
+	if err != nil {
+		s.log.Errorf("failed to pass deactivation to open away period: %v", err)
+		return err
+	}
+	if passed {
+		return nil
+	}
+
+	if _, err := s.SetUserActive(userID, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+// awayActor is recorded as the actor of reassignments made because a reviewer went away.
+const awayActor = "away-scheduler"
+
+func (s *Service) reassignOpenReviews(userID string) {
+	reviews, err := s.userRepo.GetReview(userID)
+	if err != nil {
+		s.log.Errorf("failed to get review: %v", err)
+		return
+	}
+
+	for _, pr := range reviews {
+		if pr.Status != "OPEN" {
+			continue
+		}
+		if _, _, err := s.ReassignReviewersPR(pr.ID, userID, awayActor); err != nil {
+			s.log.Errorf("failed to reassign review of pr %s from away user %s: %v", pr.ID, userID, err)
+		}
+	}
+}
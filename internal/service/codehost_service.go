@@ -0,0 +1,149 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/codehost"
+	"Pull-Requests-master/internal/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	codeHostBatch       = 50
+	codeHostMaxAttempts = 10
+	// maxBackoff caps the wait between attempts of queued jobs.
+	maxBackoff = time.Hour
+)
+
+// CodeHostConfig holds how reviewer changes are pushed back to GitHub.
+type CodeHostConfig struct {
+	// Client is nil when pushing is off.
+	Client codehost.Reviewers
+	// MaxAttempts defaults to codeHostMaxAttempts.
+	MaxAttempts int
+}
+
+// pushReviewers queues the reviewer changes of a pull request that came from GitHub and
+// wakes the retrier once the assignment is committed, so GitHub is never called while a
+// request waits. The jobs are queued in the transaction of the assignment, so a failure
+// to queue fails the assignment.
+func (s *Service) pushReviewers(prID string, added []string, removed []string) error {
+	if s.cfg.CodeHost.Client == nil {
+		return nil
+	}
+	if _, _, ok := codehost.ParseGitHubID(prID); !ok {
+		return nil
+	}
+
+	jobs := []*domain.CodeHostJob{}
+	if len(added) > 0 {
+		jobs = append(jobs, &domain.CodeHostJob{PullRequestID: prID, Action: domain.CodeHostRequest, Reviewers: added})
+	}
+	if len(removed) > 0 {
+		jobs = append(jobs, &domain.CodeHostJob{PullRequestID: prID, Action: domain.CodeHostRemove, Reviewers: removed})
+	}
+	for _, job := range jobs {
+		if _, err := s.codeHostRepo.Enqueue(job); err != nil {
+			s.log.Errorf("failed to queue reviewers of pr %s for the code host: %v", prID, err)
+			return err
+		}
+	}
+	s.whenCommitted(func(s *Service) { wake(s.codeHostQueued) })
+	return nil
+}
+
+// ProcessCodeHostJobs delivers the reviewer changes due by now.
+func (s *Service) ProcessCodeHostJobs(now time.Time) error {
+	if s.cfg.CodeHost.Client == nil {
+		return nil
+	}
+
+	jobs, err := s.codeHostRepo.GetDue(now, codeHostBatch)
+	if err != nil {
+		s.log.Errorf("failed to get due code host jobs: %v", err)
+		return err
+	}
+	for _, job := range jobs {
+		s.deliverJob(job)
+	}
+	return nil
+}
+
+// deliverJob makes one attempt at the job, then marks it done, schedules the next attempt
+// with exponential backoff or gives up after the last one.
+func (s *Service) deliverJob(job *domain.CodeHostJob) {
+	repo, number, ok := codehost.ParseGitHubID(job.PullRequestID)
+	if !ok {
+		// Retrying can't fix the id, so the job fails right away.
+		reason := fmt.Sprintf("not a github pull request: %s", job.PullRequestID)
+		s.log.Errorf("giving up on code host job %d: %s", job.ID, reason)
+		if err := s.codeHostRepo.MarkFailed(job.ID, job.Attempts, reason); err != nil {
+			s.log.Errorf("failed to mark code host job as failed: %v", err)
+		}
+		return
+	}
+
+	ctx := context.Background()
+	logins := s.githubLogins(job.Reviewers)
+	var err error
+	if job.Action == domain.CodeHostRemove {
+		err = s.cfg.CodeHost.Client.RemoveReviewers(ctx, repo, number, logins)
+	} else {
+		err = s.cfg.CodeHost.Client.RequestReviewers(ctx, repo, number, logins)
+	}
+	attempts := job.Attempts + 1
+
+	if err == nil {
+		if err := s.codeHostRepo.MarkDone(job.ID, attempts); err != nil {
+			s.log.Errorf("failed to mark code host job as done: %v", err)
+		}
+		return
+	}
+
+	maxAttempts := s.cfg.CodeHost.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = codeHostMaxAttempts
+	}
+	if attempts >= maxAttempts {
+		s.log.Errorf("giving up on code host job %d after %d attempts: %v", job.ID, attempts, err)
+		if err := s.codeHostRepo.MarkFailed(job.ID, attempts, err.Error()); err != nil {
+			s.log.Errorf("failed to mark code host job as failed: %v", err)
+		}
+		return
+	}
+
+	backoff := retryBackoff(attempts)
+	s.log.Infof("code host job %d failed, retrying in %s: %v", job.ID, backoff, err)
+	if err := s.codeHostRepo.Reschedule(job.ID, attempts, s.cfg.Clock.Now().Add(backoff), err.Error()); err != nil {
+		s.log.Errorf("failed to reschedule code host job: %v", err)
+	}
+}
+
+// retryBackoff is the wait after the given number of failed attempts: a minute, doubling
+// each time up to maxBackoff.
+func retryBackoff(attempts int) time.Duration {
+	backoff := time.Minute << (attempts - 1)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// githubLogins maps user ids back to GitHub logins through the webhook user mapping; ids
+// without one are taken as logins, as the webhook does the other way round.
+func (s *Service) githubLogins(ids []string) []string {
+	logins := map[string]string{}
+	for login, id := range s.cfg.GitHub.Users {
+		logins[id] = login
+	}
+
+	result := []string{}
+	for _, id := range ids {
+		if login, ok := logins[id]; ok {
+			result = append(result, login)
+			continue
+		}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/codeowners"
+	"Pull-Requests-master/internal/errors"
+)
+
+func (s *Service) UploadCodeOwners(repository string, content string) (*codeowners.File, error) {
+	file, err := codeowners.Parse(content)
+	if err != nil {
+		s.log.Debugf("invalid code owners of %s: %v", repository, err)
+		return nil, errors.APIError{Code: errors.ErrInvalidCodeOwners.Code, Message: err.Error()}
+	}
+
+	err = s.ownersRepo.Save(repository, content)
+	if err != nil {
+		s.log.Errorf("failed to save code owners: %v", err)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *Service) GetCodeOwners(repository string) (string, *codeowners.File, error) {
+	content, found, err := s.ownersRepo.Get(repository)
+	if err != nil {
+		s.log.Errorf("failed to get code owners: %v", err)
+		return "", nil, err
+	}
+	if !found {
+		s.log.Debugf("code owners of %s not found", repository)
+		return "", nil, errors.ErrNotFound
+	}
+
+	file, err := codeowners.Parse(content)
+	if err != nil {
+		s.log.Errorf("stored code owners of %s don't parse: %v", repository, err)
+		return "", nil, err
+	}
+
+	return content, file, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/events"
+	"context"
+)
+
+type serviceKey struct{}
+
+// Events is the bus the service announces its changes on; subscribe before serving.
+func (s *Service) Events() *events.Bus {
+	return s.bus
+}
+
+// raise publishes the event. Inside inTx the handlers run in the transaction of the
+// change, and a failing handler rolls it back.
+func (s *Service) raise(e interface{}) error {
+	ctx := context.WithValue(context.Background(), serviceKey{}, s)
+	ctx = events.WithAfterCommit(ctx, func(f func()) {
+		s.whenCommitted(func(*Service) { f() })
+	})
+	if err := s.bus.Publish(ctx, e); err != nil {
+		s.log.Errorf("failed to handle event: %v", err)
+		return err
+	}
+	return nil
+}
+
+// from returns the service that raised the event, bound to its transaction.
+func (s *Service) from(ctx context.Context) *Service {
+	if raised, ok := ctx.Value(serviceKey{}).(*Service); ok {
+		return raised
+	}
+	return s
+}
+
+// subscribe hooks the side effects of the service itself onto the bus: the outbox,
+// outbound webhooks, pushing reviewers to the code host, notifying users and Slack.
+func (s *Service) subscribe() {
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRCreated) error {
+		tx := s.from(ctx)
+		if err := tx.pushReviewers(e.PR.ID, e.PR.AssignedReviewers, nil); err != nil {
+			return err
+		}
+		if err := tx.publish(domain.EventPRCreated, e.PR); err != nil {
+			return err
+		}
+		return tx.emit(domain.EventPRCreated, e.PR.ID, e.PR)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewerAssigned) error {
+		tx := s.from(ctx)
+		event := &domain.ReviewerEvent{PullRequestID: e.PullRequestID, UserID: e.UserID}
+		if err := tx.publish(domain.EventReviewerAssigned, event); err != nil {
+			return err
+		}
+		return tx.emit(domain.EventReviewerAssigned, e.PullRequestID, event)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewerReassigned) error {
+		tx := s.from(ctx)
+		if err := tx.pushReviewers(e.PullRequestID, []string{e.NewUserID}, []string{e.OldUserID}); err != nil {
+			return err
+		}
+
+		event := &domain.ReviewerEvent{
+			PullRequestID:  e.PullRequestID,
+			UserID:         e.NewUserID,
+			ReplacedUserID: e.OldUserID,
+			Actor:          e.Actor,
+		}
+		if err := tx.publish(domain.EventReviewerReassigned, event); err != nil {
+			return err
+		}
+		removed := &domain.ReviewerEvent{PullRequestID: e.PullRequestID, UserID: e.OldUserID, Actor: e.Actor}
+		if err := tx.emit(domain.EventReviewerRemoved, e.PullRequestID, removed); err != nil {
+			return err
+		}
+		return tx.emit(domain.EventReviewerAssigned, e.PullRequestID, event)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewSubmitted) error {
+		tx := s.from(ctx)
+		event := &domain.ReviewEvent{
+			PullRequestID: e.PullRequestID,
+			UserID:        e.UserID,
+			Verdict:       e.Verdict,
+			ReviewedAt:    e.At,
+		}
+		if err := tx.publish(domain.EventReviewSubmitted, event); err != nil {
+			return err
+		}
+		return tx.emit(domain.EventReviewSubmitted, e.PullRequestID, event)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRMerged) error {
+		tx := s.from(ctx)
+		if err := tx.publish(domain.EventPRMerged, e.PR); err != nil {
+			return err
+		}
+		return tx.emit(domain.EventPRMerged, e.PR.ID, e.PR)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRClosed) error {
+		return s.from(ctx).emit(domain.EventPRClosed, e.PR.ID, e.PR)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRReopened) error {
+		return s.from(ctx).emit(domain.EventPRReopened, e.PR.ID, e.PR)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.UserActivityChanged) error {
+		tx := s.from(ctx)
+		if e.WasActive && !e.User.IsActive {
+			if err := tx.publish(domain.EventUserDeactivated, e.User); err != nil {
+				return err
+			}
+		}
+		return tx.emit(domain.EventUserActivityChanged, e.User.ID, e.User)
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.TeamChanged) error {
+		return s.from(ctx).emit(domain.EventTeamChanged, e.Team.Name, e.Team)
+	})
+	s.subscribeNotifications()
+	s.subscribeSlack()
+}
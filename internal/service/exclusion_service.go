@@ -0,0 +1,71 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"fmt"
+)
+
+func (s *Service) CreateExclusionRule(rule *domain.ExclusionRule) (*domain.ExclusionRule, error) {
+	rule.Members = trimNames(rule.Members)
+	if err := validateExclusionRule(rule); err != nil {
+		s.log.Debugf("invalid exclusion rule: %v", err)
+		return nil, errors.ErrInvalidExclusionRule
+	}
+
+	for _, id := range rule.Members {
+		exists, err := s.userRepo.CheckExist(id)
+		if err != nil {
+			s.log.Errorf("failed to check exist of user: %v", err)
+			return nil, err
+		}
+		if !exists {
+			s.log.Debugf("user with id: %s doesn't exist", id)
+			return nil, errors.ErrNotFound
+		}
+	}
+
+	newRule, err := s.exclusionRepo.Create(rule)
+	if err != nil {
+		s.log.Errorf("failed to create exclusion rule: %v", err)
+		return nil, err
+	}
+
+	return newRule, nil
+}
+
+func (s *Service) GetExclusionRules() ([]*domain.ExclusionRule, error) {
+	rules, err := s.exclusionRepo.GetAll()
+	if err != nil {
+		s.log.Errorf("failed to get exclusion rules: %v", err)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *Service) DeleteExclusionRule(id int64) error {
+	deleted, err := s.exclusionRepo.Delete(id)
+	if err != nil {
+		s.log.Errorf("failed to delete exclusion rule: %v", err)
+		return err
+	}
+	if !deleted {
+		s.log.Debugf("exclusion rule with id: %d not found", id)
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func validateExclusionRule(rule *domain.ExclusionRule) error {
+	switch rule.Kind {
+	case domain.ExclusionNotTogether, domain.ExclusionNoCrossReview:
+	default:
+		return fmt.Errorf("unknown kind %q", rule.Kind)
+	}
+	if len(rule.Members) < 2 {
+		return fmt.Errorf("a rule needs at least two members")
+	}
+	return nil
+}
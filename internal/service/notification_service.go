@@ -0,0 +1,165 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
+	"Pull-Requests-master/internal/notify"
+	"context"
+	"fmt"
+)
+
+// NotifyConfig holds the channels notifications go through, by name; without any,
+// nobody is notified.
+type NotifyConfig struct {
+	Channels map[string]notify.Channel
+}
+
+// GetNotificationPreferences returns what the user chose, or everything by email when
+// they haven't.
+func (s *Service) GetNotificationPreferences(userID string) (*domain.NotificationPreferences, error) {
+	if _, err := s.GetUser(userID); err != nil {
+		return nil, err
+	}
+	return s.preferences(userID)
+}
+
+func (s *Service) SetNotificationPreferences(prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	prefs.Channels = trimNames(prefs.Channels)
+	prefs.Events = trimNames(prefs.Events)
+	if err := validatePreferences(prefs); err != nil {
+		s.log.Debugf("invalid notification preferences: %v", err)
+		return nil, errors.APIError{Code: errors.ErrInvalidPreferences.Code, Message: err.Error()}
+	}
+
+	if _, err := s.GetUser(prefs.UserID); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.notificationRepo.SavePreferences(prefs)
+	if err != nil {
+		s.log.Errorf("failed to save notification preferences: %v", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (s *Service) preferences(userID string) (*domain.NotificationPreferences, error) {
+	prefs, found, err := s.notificationRepo.GetPreferences(userID)
+	if err != nil {
+		s.log.Errorf("failed to get notification preferences: %v", err)
+		return nil, err
+	}
+	if !found {
+		return &domain.NotificationPreferences{
+			UserID:   userID,
+			Channels: []string{domain.ChannelEmail},
+			Events:   append([]string(nil), domain.NotifyKinds...),
+		}, nil
+	}
+
+	return prefs, nil
+}
+
+// subscribeNotifications notifies reviewers on a background worker once the changes about
+// them are committed. A notification that can't be sent is only logged.
+func (s *Service) subscribeNotifications() {
+	if len(s.cfg.Notify.Channels) == 0 {
+		return
+	}
+
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewerAssigned) error {
+		s.backgroundAfterCommit(ctx, "notification", func() {
+			s.notifyReviewers(domain.NotifyAssigned, e.PullRequestID, []string{e.UserID}, "")
+		})
+		return nil
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewerReassigned) error {
+		s.backgroundAfterCommit(ctx, "notification", func() {
+			s.notifyReviewers(domain.NotifyAssigned, e.PullRequestID, []string{e.NewUserID}, e.OldUserID)
+			s.notifyReviewers(domain.NotifyReassignedAway, e.PullRequestID, []string{e.OldUserID}, e.NewUserID)
+		})
+		return nil
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRMerged) error {
+		s.backgroundAfterCommit(ctx, "notification", func() {
+			reviewers, err := s.prRepo.GetReviewrs(e.PR.ID)
+			if err != nil {
+				s.log.Errorf("failed to get reviewers of pr %s to notify: %v", e.PR.ID, err)
+				return
+			}
+			s.notifyReviewers(domain.NotifyMerged, e.PR.ID, reviewers, "")
+		})
+		return nil
+	})
+}
+
+func (s *Service) notifyReviewers(kind string, prID string, userIDs []string, other string) {
+	pr, err := s.prRepo.GetByID(prID)
+	if err != nil {
+		s.log.Errorf("failed to get pr %s to notify about: %v", prID, err)
+		return
+	}
+	for _, userID := range userIDs {
+		s.notify(kind, pr, userID, other)
+	}
+}
+
+func (s *Service) notify(kind string, pr *domain.PullRequest, userID string, other string) {
+	prefs, err := s.preferences(userID)
+	if err != nil || !contains(prefs.Events, kind) {
+		return
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		s.log.Errorf("failed to get user %s to notify: %v", userID, err)
+		return
+	}
+
+	msg, err := notify.Render(kind, &notify.Data{User: user, PR: pr, Other: other})
+	if err != nil {
+		s.log.Errorf("failed to render %s notification: %v", kind, err)
+		return
+	}
+	for _, name := range prefs.Channels {
+		channel, ok := s.cfg.Notify.Channels[name]
+		if !ok {
+			continue
+		}
+		err := channel.Send(context.Background(), user, msg)
+		if err == notify.ErrNoAddress {
+			s.log.Debugf("user %s has no %s address to notify", userID, name)
+			continue
+		}
+		if err != nil {
+			s.log.Errorf("failed to send %s notification of pr %s to %s by %s: %v", kind, pr.ID, userID, name, err)
+		}
+	}
+}
+
+func validatePreferences(prefs *domain.NotificationPreferences) error {
+	if prefs.UserID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	for _, channel := range prefs.Channels {
+		if !contains(domain.NotifyChannels, channel) {
+			return fmt.Errorf("unknown channel %q", channel)
+		}
+	}
+	for _, event := range prefs.Events {
+		if !contains(domain.NotifyKinds, event) {
+			return fmt.Errorf("unknown notification %q", event)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/outbox"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// outboxBatch is how many messages one relay run publishes at most.
+const outboxBatch = 100
+
+// OutboxConfig holds where the domain events of the outbox go.
+type OutboxConfig struct {
+	// Sink is nil when the events are only stored.
+	Sink outbox.Sink
+	// Retention is how long published messages are kept; zero keeps them.
+	Retention time.Duration
+}
+
+// inTx runs fn on a copy of the service whose repositories share one transaction, so its
+// changes and their outbox messages are stored together or not at all. Calls nested in fn
+// join the same transaction.
+func (s *Service) inTx(fn func(tx *Service) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		s.log.Errorf("failed to begin transaction: %v", err)
+		return err
+	}
+	txs := *s
+	txs.tx = tx
+	txs.afterCommit = &[]func(*Service){}
+	txs.bind(tx)
+
+	if err := fn(&txs); err != nil {
+		if err := tx.Rollback(); err != nil {
+			s.log.Errorf("failed to roll back transaction: %v", err)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		s.log.Errorf("failed to commit transaction: %v", err)
+		return err
+	}
+
+	for _, f := range *txs.afterCommit {
+		f(s)
+	}
+	return nil
+}
+
+// whenCommitted runs f once the current transaction commits, or right away outside of
+// one. Calls to the outside world go there, so they never act on a change that is rolled
+// back and never hold the transaction open.
+func (s *Service) whenCommitted(f func(s *Service)) {
+	if s.afterCommit == nil {
+		f(s)
+		return
+	}
+	*s.afterCommit = append(*s.afterCommit, f)
+}
+
+// emit writes a domain event to the outbox. Inside inTx it is stored with the change.
+func (s *Service) emit(event string, aggregateID string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.log.Errorf("failed to marshal %s event: %v", event, err)
+		return err
+	}
+
+	err = s.outboxRepo.Add(&domain.OutboxMessage{EventType: event, AggregateID: aggregateID, Payload: payload})
+	if err != nil {
+		s.log.Errorf("failed to write %s event to the outbox: %v", event, err)
+		return err
+	}
+	return nil
+}
+
+// RelayOutbox publishes the pending outbox messages in the order they were written. It
+// stops at the first failure so the order holds, and the message is tried again on the
+// next run; a message published but not marked is published again, so delivery is at
+// least once. It returns how many messages were published.
+func (s *Service) RelayOutbox() (int, error) {
+	if s.cfg.Outbox.Sink == nil {
+		return 0, nil
+	}
+
+	msgs, err := s.outboxRepo.GetUnpublished(outboxBatch)
+	if err != nil {
+		s.log.Errorf("failed to get unpublished outbox messages: %v", err)
+		return 0, err
+	}
+
+	ctx := context.Background()
+	published := 0
+	for _, msg := range msgs {
+		if err := s.cfg.Outbox.Sink.Publish(ctx, msg); err != nil {
+			s.log.Errorf("failed to publish outbox message %d: %v", msg.ID, err)
+			if err := s.outboxRepo.MarkFailed(msg.ID, err.Error()); err != nil {
+				s.log.Errorf("failed to mark outbox message as failed: %v", err)
+			}
+			return published, err
+		}
+		if err := s.outboxRepo.MarkPublished(msg.ID, s.cfg.Clock.Now()); err != nil {
+			s.log.Errorf("failed to mark outbox message as published: %v", err)
+			return published, err
+		}
+		published++
+	}
+
+	if s.cfg.Outbox.Retention > 0 && len(msgs) < outboxBatch {
+		deleted, err := s.outboxRepo.DeletePublished(s.cfg.Clock.Now().Add(-s.cfg.Outbox.Retention))
+		if err != nil {
+			s.log.Errorf("failed to delete published outbox messages: %v", err)
+			return published, err
+		}
+		if deleted > 0 {
+			s.log.Debugf("deleted %d published outbox messages", deleted)
+		}
+	}
+	return published, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+)
+
+// PreviewAssignment runs the assignment for a pull request that doesn't exist yet and
+// reports who it would pick from whom, writing nothing.
+func (s *Service) PreviewAssignment(pr *domain.NewPullRequest) (*domain.AssignmentPreview, error) {
+	author, coAuthors, err := s.checkAuthors(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.repository(pr.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &domain.PullRequest{
+		PullRequestShort: pr.PullRequestShort,
+		CoAuthorIDs:      coAuthors,
+		Repository:       pr.Repository,
+		Labels:           normalizeTags(pr.Labels),
+		ChangedFiles:     pr.ChangedFiles,
+	}
+	plan, err := s.planReviewers(draft, author, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped, err := s.skippedCandidates(draft, author, repo, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AssignmentPreview{
+		Strategy:          plan.strategy.Name(),
+		Rule:              plan.source,
+		RequiredReviewers: plan.required,
+		Reviewers:         memberIDs(plan.reviewers),
+		Pool:              memberIDs(plan.pool),
+		Skipped:           skipped,
+		Assignment:        plan.info,
+	}, nil
+}
+
+// skippedCandidates explains why the authors, the mandatory reviewers, the people the
+// exclusion rules removed and the members of the teams in scope are not in the pool.
+func (s *Service) skippedCandidates(pr *domain.PullRequest, author *domain.User, repo *domain.Repository,
+	plan *reviewerPlan) ([]*domain.SkippedCandidate, error) {
+	skipped := []*domain.SkippedCandidate{}
+	seen := map[string]bool{}
+	for _, id := range memberIDs(plan.pool) {
+		seen[id] = true
+	}
+
+	for _, id := range authors(pr) {
+		skipped = addSkipped(skipped, seen, id, domain.SkipAuthor, 0)
+	}
+	for _, reviewer := range plan.required {
+		skipped = addSkipped(skipped, seen, reviewer.UserID, domain.SkipRequiredReviewer, 0)
+	}
+	if plan.info != nil {
+		for _, excluded := range plan.info.Excluded {
+			skipped = addSkipped(skipped, seen, excluded.UserID, domain.SkipExclusionRule, excluded.RuleID)
+		}
+	}
+
+	teams := []string{author.TeamName}
+	if repo != nil && len(repo.Teams) > 0 {
+		teams = repo.Teams
+	}
+	now := s.cfg.Clock.Now()
+	for _, name := range teams {
+		team, err := s.teamRepo.GetByName(name)
+		if err != nil {
+			s.log.Errorf("failed to get team: %v", err)
+			return nil, err
+		}
+		for _, member := range team.Members {
+			if seen[member.ID] {
+				continue
+			}
+			if !member.IsActive {
+				skipped = addSkipped(skipped, seen, member.ID, domain.SkipInactive, 0)
+				continue
+			}
+			away, err := s.awayRepo.IsAway(member.ID, now)
+			if err != nil {
+				s.log.Errorf("failed to check away of user: %v", err)
+				return nil, err
+			}
+			if away {
+				skipped = addSkipped(skipped, seen, member.ID, domain.SkipAway, 0)
+				continue
+			}
+			if plan.source == domain.RuleCodeOwners {
+				skipped = addSkipped(skipped, seen, member.ID, domain.SkipNotOwner, 0)
+			}
+		}
+	}
+	return skipped, nil
+}
+
+func addSkipped(skipped []*domain.SkippedCandidate, seen map[string]bool, userID string, reason string,
+	ruleID int64) []*domain.SkippedCandidate {
+	if seen[userID] {
+		return skipped
+	}
+	seen[userID] = true
+	return append(skipped, &domain.SkippedCandidate{UserID: userID, Reason: reason, RuleID: ruleID})
+}
+
+func memberIDs(users []*domain.User) []string {
+	ids := []string{}
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
@@ -1,30 +1,114 @@
 package service
 
 import (
+	"Pull-Requests-master/internal/assignment"
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
 	"Pull-Requests-master/internal/repository"
 	"Pull-Requests-master/package/logger"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 type Service struct {
-	userRepo repository.UserRepository
-	teamRepo repository.TeamRepository
-	prRepo   repository.PullRequestRepository
-	log      *logger.Logger
+	db *sql.DB
+	// tx and afterCommit are set on the copy of the service inTx runs with.
+	tx          *sql.Tx
+	afterCommit *[]func(s *Service)
+	bus         *events.Bus
+
+	userRepo         repository.UserRepository
+	teamRepo         repository.TeamRepository
+	prRepo           repository.PullRequestRepository
+	awayRepo         repository.AwayRepository
+	ownersRepo       repository.CodeOwnersRepository
+	repoRepo         repository.RepositoryRepository
+	groupRepo        repository.ReviewerGroupRepository
+	exclusionRepo    repository.ExclusionRepository
+	assignmentRepo   repository.AssignmentRepository
+	codeHostRepo     repository.CodeHostJobRepository
+	subscriptionRepo repository.SubscriptionRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	outboxRepo       repository.OutboxRepository
+	notificationRepo repository.NotificationRepository
+	telegramRepo     repository.TelegramRepository
+	cfg              Config
+	log              *logger.Logger
+
+	// codeHostQueued and deliveriesQueued wake the runners of the queues; background
+	// feeds the workers of inBackground.
+	codeHostQueued   chan struct{}
+	deliveriesQueued chan struct{}
+	background       chan func()
 }
 
-func NewService(db *sql.DB, logger *logger.Logger) *Service {
-	return &Service{
-		userRepo: repository.NewUserRepository(db, logger),
-		teamRepo: repository.NewTeamRepository(db, logger),
-		prRepo:   repository.NewPullRequestRepository(db, logger),
-		log:      logger,
+// Config holds the reviewer assignment settings of the service.
+type Config struct {
+	Strategy assignment.Strategy
+	// Strategies are the ones repository policies may choose, by name.
+	Strategies map[string]assignment.Strategy
+	Clock      assignment.Clock
+	// Hours are the default working hours, also used to count ReviewSLA.
+	Hours assignment.WorkingHours
+	// ReviewSLA is the working time a review is due in; zero disables due dates.
+	ReviewSLA time.Duration
+	// PairingWindow is how far back reviews count towards author-reviewer pairings.
+	PairingWindow time.Duration
+	GitHub        WebhookConfig
+	GitLab        WebhookConfig
+	// CodeHost pushes assigned reviewers back to GitHub.
+	CodeHost CodeHostConfig
+	// Outbound posts events to webhook subscriptions.
+	Outbound OutboundConfig
+	// Outbox publishes the domain events.
+	Outbox OutboxConfig
+	// Notify tells users about their reviews.
+	Notify NotifyConfig
+	// Slack posts to team channels and takes the /review command.
+	Slack SlackConfig
+	// Telegram answers the bot commands and sends notifications to linked chats.
+	Telegram TelegramConfig
+}
+
+func NewService(db *sql.DB, logger *logger.Logger, cfg Config) *Service {
+	s := &Service{
+		db:  db,
+		bus: events.New(),
+		cfg: cfg,
+		log: logger,
+
+		codeHostQueued:   make(chan struct{}, 1),
+		deliveriesQueued: make(chan struct{}, 1),
 	}
+	s.bind(db)
+	s.startWorkers()
+	s.addTelegramChannel()
+	s.subscribe()
+	return s
 }
 
-func (s *Service) CreatePR(pr *domain.PullRequestShort) (*domain.PullRequest, error) {
+// bind points the repositories at the database or at a transaction.
+func (s *Service) bind(db repository.DBTX) {
+	s.userRepo = repository.NewUserRepository(db, s.log)
+	s.teamRepo = repository.NewTeamRepository(db, s.log)
+	s.prRepo = repository.NewPullRequestRepository(db, s.log)
+	s.awayRepo = repository.NewAwayRepository(db, s.log)
+	s.ownersRepo = repository.NewCodeOwnersRepository(db, s.log)
+	s.repoRepo = repository.NewRepositoryRepository(db, s.log)
+	s.groupRepo = repository.NewReviewerGroupRepository(db, s.log)
+	s.exclusionRepo = repository.NewExclusionRepository(db, s.log)
+	s.assignmentRepo = repository.NewAssignmentRepository(db, s.log)
+	s.codeHostRepo = repository.NewCodeHostJobRepository(db, s.log)
+	s.subscriptionRepo = repository.NewSubscriptionRepository(db, s.log)
+	s.deliveryRepo = repository.NewWebhookDeliveryRepository(db, s.log)
+	s.outboxRepo = repository.NewOutboxRepository(db, s.log)
+	s.notificationRepo = repository.NewNotificationRepository(db, s.log)
+	s.telegramRepo = repository.NewTelegramRepository(db, s.log)
+}
+
+func (s *Service) CreatePR(pr *domain.NewPullRequest) (*domain.PullRequest, error) {
 	exists, err := s.prRepo.CheckPRExist(pr.ID)
 	if err != nil {
 		s.log.Errorf("failed to check exist of pr: %v", err)
@@ -36,17 +120,155 @@ func (s *Service) CreatePR(pr *domain.PullRequestShort) (*domain.PullRequest, er
 		return nil, errors.ErrPRExists
 	}
 
+	author, coAuthors, err := s.checkAuthors(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.repository(pr.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	dueAt, err := s.reviewDueAt(author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
 	pr.Status = "OPEN"
-	newPR, err := s.prRepo.Create(pr)
+	newPR := &domain.PullRequest{
+		PullRequestShort: pr.PullRequestShort,
+		CoAuthorIDs:      coAuthors,
+		Repository:       pr.Repository,
+		Labels:           normalizeTags(pr.Labels),
+		ChangedFiles:     pr.ChangedFiles,
+		DueAt:            dueAt,
+	}
+	plan, err := s.planReviewers(newPR, author, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.inTx(func(tx *Service) error {
+		newPR, err = tx.storePR(newPR, plan)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPR, nil
+}
+
+// storePR saves the new pull request with its planned reviewers and announces them.
+func (s *Service) storePR(newPR *domain.PullRequest, plan *reviewerPlan) (*domain.PullRequest, error) {
+	newPR, err := s.prRepo.Create(newPR)
 	if err != nil {
 		s.log.Errorf("failed to create pr: %v", err)
 		return nil, err
 	}
 
+	for _, reviewer := range plan.required {
+		err = s.prRepo.AddReviewer(newPR.ID, reviewer.UserID, reviewer.Group)
+		if err != nil {
+			s.log.Errorf("failed to add reviewer: %v", err)
+			return nil, err
+		}
+		err = s.recordAssignment(&domain.AssignmentRecord{
+			PullRequestID: newPR.ID,
+			UserID:        reviewer.UserID,
+			Action:        domain.AssignmentCreated,
+			Strategy:      plan.strategy.Name(),
+			Rule:          domain.RuleReviewerGroup,
+			RuleDetail:    reviewer.Group,
+		})
+		if err != nil {
+			return nil, err
+		}
+		newPR.AssignedReviewers = append(newPR.AssignedReviewers, reviewer.UserID)
+	}
+	newPR.RequiredReviewers = plan.required
+	newPR.Assignment = plan.info
+
+	for _, reviewer := range plan.reviewers {
+		err = s.prRepo.AddReviewer(newPR.ID, reviewer.ID, "")
+		if err != nil {
+			s.log.Errorf("failed to add reviewer: %v", err)
+			return nil, err
+		}
+		err = s.recordAssignment(&domain.AssignmentRecord{
+			PullRequestID: newPR.ID,
+			UserID:        reviewer.ID,
+			Action:        domain.AssignmentCreated,
+			Strategy:      plan.strategy.Name(),
+			Rule:          plan.source,
+			RuleDetail:    ruleDetail(plan.source, plan.info, reviewer),
+			Fallback:      plan.info != nil && plan.info.FallbackToTeam,
+		})
+		if err != nil {
+			return nil, err
+		}
+		newPR.AssignedReviewers = append(newPR.AssignedReviewers, reviewer.ID)
+	}
+	if err := s.raise(events.PRCreated{PR: newPR}); err != nil {
+		return nil, err
+	}
+	for _, reviewer := range newPR.RequiredReviewers {
+		err := s.raise(events.ReviewerAssigned{PullRequestID: newPR.ID, UserID: reviewer.UserID, Group: reviewer.Group})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, reviewer := range plan.reviewers {
+		if err := s.raise(events.ReviewerAssigned{PullRequestID: newPR.ID, UserID: reviewer.ID}); err != nil {
+			return nil, err
+		}
+	}
+
 	return newPR, nil
 }
 
+// checkAuthors loads the author and returns the cleaned up co-authors, making sure
+// they all exist.
+func (s *Service) checkAuthors(pr *domain.NewPullRequest) (*domain.User, []string, error) {
+	author, err := s.GetUser(pr.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coAuthors := coAuthorIDs(pr.CoAuthorIDs, pr.AuthorID)
+	for _, id := range coAuthors {
+		_, err = s.GetUser(id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return author, coAuthors, nil
+}
+
+// coAuthorIDs drops blanks, repeats and the author from the co-authors.
+func coAuthorIDs(ids []string, authorID string) []string {
+	result := []string{}
+	seen := map[string]bool{authorID: true}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// MergePR merges the pull request once it has the approvals its repository requires.
 func (s *Service) MergePR(id string) (*domain.PullRequest, error) {
+	return s.merge(id, true)
+}
+
+// merge marks the pull request merged; gated refuses it while approvals are missing,
+// which code host merges skip as they already happened.
+func (s *Service) merge(id string, gated bool) (*domain.PullRequest, error) {
 	exists, err := s.prRepo.CheckPRExist(id)
 	if err != nil {
 		s.log.Errorf("failed to check exist of pr: %v", err)
@@ -69,32 +291,342 @@ func (s *Service) MergePR(id string) (*domain.PullRequest, error) {
 		return pr, nil
 	}
 
-	newPR, err := s.prRepo.Merge(id)
+	if gated {
+		repo, err := s.repository(pr.Repository)
+		if err != nil {
+			return nil, err
+		}
+		if repo != nil && approvals(pr) < repo.Policy.RequiredApprovals {
+			s.log.Debugf("pr with id: %s has %d of %d approvals", id, approvals(pr), repo.Policy.RequiredApprovals)
+			return nil, errors.ErrNotApproved
+		}
+	}
+
+	var newPR *domain.PullRequest
+	err = s.inTx(func(tx *Service) error {
+		newPR, err = tx.prRepo.Merge(id)
+		if err != nil {
+			tx.log.Errorf("failed to merge pr: %v", err)
+			return err
+		}
+		return tx.raise(events.PRMerged{PR: newPR})
+	})
 	if err != nil {
-		s.log.Errorf("failed to merge pr: %v", err)
 		return nil, err
 	}
 
 	return newPR, nil
 }
 
-func (s *Service) ReassignReviewersPR(id string, oldRevID string) (*domain.PullRequest, error) {
+// approvals counts the people whose latest review of the pull request approves it.
+func approvals(pr *domain.PullRequest) int {
+	count := 0
+	for _, entry := range pr.ReviewerHistory {
+		if entry.UnassignedAt == nil && entry.Verdict == domain.VerdictApproved {
+			count++
+		}
+	}
+	return count
+}
+
+// ClosePR closes an open pull request without merging it.
+func (s *Service) ClosePR(id string) (*domain.PullRequest, error) {
+	return s.setStatus(id, "OPEN", "CLOSED")
+}
+
+// ReopenPR opens a closed pull request again; its reviewers stay.
+func (s *Service) ReopenPR(id string) (*domain.PullRequest, error) {
+	return s.setStatus(id, "CLOSED", "OPEN")
+}
+
+// setStatus moves the pull request from one status to another, leaving it alone when it
+// is in any other status.
+func (s *Service) setStatus(id string, from string, to string) (*domain.PullRequest, error) {
+	exists, err := s.prRepo.CheckPRExist(id)
+	if err != nil {
+		s.log.Errorf("failed to check exist of pr: %v", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Debugf("pull request with id: %s doesn't exist", id)
+		return nil, errors.ErrNotFound
+	}
+
 	pr, err := s.prRepo.GetByID(id)
 	if err != nil {
 		s.log.Errorf("failed to get pr by id: %v", err)
 		return nil, err
 	}
+	if pr.Status != from {
+		s.log.Debugf("pr with id: %s is %s, not %s", id, pr.Status, from)
+		return pr, nil
+	}
+
+	var newPR *domain.PullRequest
+	err = s.inTx(func(tx *Service) error {
+		newPR, err = tx.prRepo.SetStatus(id, to)
+		if err != nil {
+			tx.log.Errorf("failed to set status of pr: %v", err)
+			return err
+		}
+		if to == "CLOSED" {
+			return tx.raise(events.PRClosed{PR: newPR})
+		}
+		return tx.raise(events.PRReopened{PR: newPR})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPR, nil
+}
+
+// ReassignReviewersPR replaces the reviewer and returns who replaced them, empty when nobody
+// could; actor is who asked for it, recorded with the new assignment.
+func (s *Service) ReassignReviewersPR(id string, oldRevID string, actor string) (*domain.PullRequest, string, error) {
+	pr, err := s.prRepo.GetByID(id)
+	if err != nil {
+		s.log.Errorf("failed to get pr by id: %v", err)
+		return nil, "", err
+	}
 
 	if pr.Status == "MERGED" {
 		s.log.Debugf("pr with id: %s merged", id)
-		return nil, errors.ErrPRMerged
+		return nil, "", errors.ErrPRMerged
+	}
+	if pr.Status != "OPEN" {
+		s.log.Debugf("pr with id: %s is %s", id, pr.Status)
+		return nil, "", errors.ErrPRClosed
+	}
+
+	if !contains(pr.AssignedReviewers, oldRevID) {
+		s.log.Debugf("user %s doesn't review pr %s", oldRevID, id)
+		return nil, "", errors.ErrNotAssigned
+	}
+
+	repo, err := s.repository(pr.Repository)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ex, err := s.exclusions()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A mandatory reviewer is replaced from the same group, anyone else from the regular pool.
+	group, err := s.requiredGroup(pr, oldRevID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	strategy := s.strategy(repo)
+	var picked []*domain.User
+	var info *domain.AssignmentInfo
+	groupName := ""
+	source := domain.RuleReviewerGroup
+	if group != nil {
+		groupName = group.Name
+		candidates, err := s.groupMembers(group, append(authors(pr), pr.AssignedReviewers...)...)
+		if err != nil {
+			return nil, "", err
+		}
+		pool := len(candidates)
+		candidates, excluded := ex.Filter(candidates, authors(pr), stayingReviewers(pr, oldRevID))
+		info = reportExclusions(info, excluded, pool > 0 && len(candidates) == 0)
+
+		pairings, err := s.pairings(pr.AuthorID)
+		if err != nil {
+			return nil, "", err
+		}
+		picked, err = s.selectReviewers(strategy, assignment.Request{
+			Candidates: candidates,
+			Count:      1,
+			Pairings:   pairings,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		picked, source, info, err = s.optionalReplacement(pr, repo, strategy, oldRevID, ex)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if len(picked) == 0 {
+		s.log.Debugf("no replacement candidate for reviewer %s on pr %s", oldRevID, id)
+		pr.Assignment = info
+		return pr, "", nil
+	}
+
+	detail := groupName
+	if group == nil {
+		detail = ruleDetail(source, info, picked[0])
+	}
+	newRevID := picked[0].ID
+	err = s.inTx(func(tx *Service) error {
+		err := tx.prRepo.AddReviewer(id, newRevID, groupName)
+		if err != nil {
+			tx.log.Errorf("failed to add reviewer: %v", err)
+			return err
+		}
+		unassigned, err := tx.prRepo.UnassignReviewer(id, oldRevID, newRevID)
+		if err != nil {
+			tx.log.Errorf("failed to unassign reviewer: %v", err)
+			return err
+		}
+		if !unassigned {
+			tx.log.Debugf("user %s was unassigned from pr %s meanwhile", oldRevID, id)
+			return errors.ErrNotAssigned
+		}
+		err = tx.recordAssignment(&domain.AssignmentRecord{
+			PullRequestID:  id,
+			UserID:         newRevID,
+			Action:         domain.AssignmentReassigned,
+			Strategy:       strategy.Name(),
+			Rule:           source,
+			RuleDetail:     detail,
+			Fallback:       info != nil && info.FallbackToTeam,
+			Actor:          actor,
+			ReplacedUserID: oldRevID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.raise(events.ReviewerReassigned{
+			PullRequestID: id,
+			OldUserID:     oldRevID,
+			NewUserID:     newRevID,
+			Actor:         actor,
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	newPR, err := s.prRepo.GetByID(id)
+	if err != nil {
+		s.log.Errorf("failed to get pr by id: %v", err)
+		return nil, "", err
+	}
+	newPR.Assignment = info
+
+	return newPR, newRevID, nil
+}
+
+// GetAssignments explains how the reviewers got on the pull request, only the given one
+// unless userID is empty.
+func (s *Service) GetAssignments(prID string, userID string) ([]*domain.AssignmentRecord, error) {
+	exists, err := s.prRepo.CheckPRExist(prID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of pr: %v", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Debugf("pull request with id: %s doesn't exist", prID)
+		return nil, errors.ErrNotFound
 	}
 
-	newPR, err := s.prRepo.Reassign(id, oldRevID)
+	records, err := s.assignmentRepo.GetByPR(prID, userID)
 	if err != nil {
-		s.log.Errorf("failed to Reassign reviewer: %v", err)
+		s.log.Errorf("failed to get assignments: %v", err)
 		return nil, err
 	}
+	return records, nil
+}
 
-	return newPR, nil
+// reassignOwn hands the user's own review to someone else, for the chat commands. It
+// returns who took it over, empty when nobody could.
+func (s *Service) reassignOwn(userID string, prID string) (string, error) {
+	exists, err := s.prRepo.CheckPRExist(prID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of pr: %v", err)
+		return "", err
+	}
+	if !exists {
+		s.log.Debugf("pr with id: %s not found", prID)
+		return "", errors.ErrNotFound
+	}
+
+	_, replacedBy, err := s.ReassignReviewersPR(prID, userID, userID)
+	return replacedBy, err
+}
+
+// requiredGroup returns the mandatory group the reviewer was picked from, or nil for
+// optional reviewers and groups deleted since.
+func (s *Service) requiredGroup(pr *domain.PullRequest, revID string) (*domain.ReviewerGroup, error) {
+	for _, reviewer := range pr.RequiredReviewers {
+		if reviewer.UserID != revID {
+			continue
+		}
+		group, found, err := s.groupRepo.GetByName(reviewer.Group)
+		if err != nil {
+			s.log.Errorf("failed to get reviewer group: %v", err)
+			return nil, err
+		}
+		if !found {
+			s.log.Debugf("reviewer group %s of pr %s no longer exists", reviewer.Group, pr.ID)
+			return nil, nil
+		}
+		return group, nil
+	}
+	return nil, nil
+}
+
+func (s *Service) optionalReplacement(pr *domain.PullRequest, repo *domain.Repository, strategy assignment.Strategy,
+	oldRevID string, ex assignment.Exclusions) ([]*domain.User, string, *domain.AssignmentInfo, error) {
+	candidates, source, info, err := s.candidates(pr, repo)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	candidates = assignment.Exclude(candidates, pr.AssignedReviewers...)
+	pool := len(candidates)
+	candidates, excluded := ex.Filter(candidates, authors(pr), stayingReviewers(pr, oldRevID))
+	info = reportExclusions(info, excluded, pool > 0 && len(candidates) == 0)
+
+	// The replacement has to cover the labels only the old reviewer had skills for.
+	remaining, err := s.reviewers(pr.AssignedReviewers, oldRevID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	labels := assignment.UncoveredLabels(pr.Labels, remaining)
+
+	author, err := s.userRepo.GetByID(pr.AuthorID)
+	if err != nil {
+		s.log.Errorf("failed to get user by id: %v", err)
+		return nil, "", nil, err
+	}
+	rule, err := s.seniorityRule(author.TeamName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	pairings, err := s.pairings(pr.AuthorID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	picked, err := s.selectReviewers(strategy, assignment.Request{
+		Candidates: candidates,
+		Count:      1,
+		Labels:     labels,
+		Existing:   remaining,
+		Seniority:  rule,
+		Exclusions: ex,
+		Pairings:   pairings,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return picked, source, info, nil
+}
+
+// stayingReviewers are the reviewers of the pull request other than the one being replaced.
+func stayingReviewers(pr *domain.PullRequest, oldRevID string) []string {
+	staying := []string{}
+	for _, id := range pr.AssignedReviewers {
+		if id != oldRevID {
+			staying = append(staying, id)
+		}
+	}
+	return staying
 }
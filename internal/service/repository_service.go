@@ -0,0 +1,159 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/assignment"
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"fmt"
+)
+
+// maxReviewerCount caps the reviewer count a repository policy may ask for.
+const maxReviewerCount = 10
+
+func (s *Service) CreateRepository(repo *domain.Repository) (*domain.Repository, error) {
+	_, exists, err := s.repoRepo.Get(repo.Name)
+	if err != nil {
+		s.log.Errorf("failed to get repository: %v", err)
+		return nil, err
+	}
+
+	if exists {
+		s.log.Debugf("repository with name: %s exist", repo.Name)
+		return nil, errors.ErrRepositoryExists
+	}
+
+	err = s.checkRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repoRepo.Create(repo)
+	if err != nil {
+		s.log.Errorf("failed to create repository: %v", err)
+		return nil, err
+	}
+
+	return s.GetRepository(repo.Name)
+}
+
+func (s *Service) UpdateRepository(repo *domain.Repository) (*domain.Repository, error) {
+	_, exists, err := s.repoRepo.Get(repo.Name)
+	if err != nil {
+		s.log.Errorf("failed to get repository: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("repository with name: %s doesn't exist", repo.Name)
+		return nil, errors.ErrNotFound
+	}
+
+	err = s.checkRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repoRepo.Update(repo)
+	if err != nil {
+		s.log.Errorf("failed to update repository: %v", err)
+		return nil, err
+	}
+
+	return s.GetRepository(repo.Name)
+}
+
+func (s *Service) GetRepository(name string) (*domain.Repository, error) {
+	repo, exists, err := s.repoRepo.Get(name)
+	if err != nil {
+		s.log.Errorf("failed to get repository: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("repository with name: %s doesn't exist", name)
+		return nil, errors.ErrNotFound
+	}
+
+	return repo, nil
+}
+
+// checkRepository validates the policy and makes sure all teams of the repository exist.
+func (s *Service) checkRepository(repo *domain.Repository) error {
+	if err := s.validatePolicy(&repo.Policy); err != nil {
+		s.log.Debugf("invalid policy of repository %s: %v", repo.Name, err)
+		return errors.ErrInvalidPolicy
+	}
+
+	teams := []string{}
+	seen := map[string]bool{}
+	for _, team := range repo.Teams {
+		if team == "" || seen[team] {
+			continue
+		}
+		seen[team] = true
+
+		exists, err := s.teamRepo.CheckExist(team)
+		if err != nil {
+			s.log.Errorf("failed to check exist of team: %v", err)
+			return err
+		}
+		if !exists {
+			s.log.Debugf("team with name: %s dosn't exist", team)
+			return errors.ErrNotFound
+		}
+		teams = append(teams, team)
+	}
+	repo.Teams = teams
+
+	return nil
+}
+
+func (s *Service) validatePolicy(p *domain.RepositoryPolicy) error {
+	if p.ReviewerCount < 0 || p.ReviewerCount > maxReviewerCount {
+		return fmt.Errorf("reviewer_count must be between 0 and %d", maxReviewerCount)
+	}
+	if p.Strategy != "" {
+		if _, ok := s.cfg.Strategies[p.Strategy]; !ok {
+			return fmt.Errorf("unknown strategy %q", p.Strategy)
+		}
+	}
+	count := p.ReviewerCount
+	if count == 0 {
+		count = assignment.MaxReviewers
+	}
+	if p.RequiredApprovals < 0 || p.RequiredApprovals > count {
+		return fmt.Errorf("required_approvals must be between 0 and the reviewer count")
+	}
+	return nil
+}
+
+// reviewerCount is how many reviewers a new pull request of the repository gets.
+func reviewerCount(repo *domain.Repository) int {
+	if repo == nil || repo.Policy.ReviewerCount == 0 {
+		return assignment.MaxReviewers
+	}
+	return repo.Policy.ReviewerCount
+}
+
+// repository returns the registered repository of the pull request, or nil.
+func (s *Service) repository(name string) (*domain.Repository, error) {
+	if name == "" {
+		return nil, nil
+	}
+	repo, _, err := s.repoRepo.Get(name)
+	if err != nil {
+		s.log.Errorf("failed to get repository: %v", err)
+		return nil, err
+	}
+	return repo, nil
+}
+
+// strategy picks the assignment strategy of the repository's policy.
+func (s *Service) strategy(repo *domain.Repository) assignment.Strategy {
+	if repo != nil && repo.Policy.Strategy != "" {
+		if strategy, ok := s.cfg.Strategies[repo.Policy.Strategy]; ok {
+			return strategy
+		}
+	}
+	return s.cfg.Strategy
+}
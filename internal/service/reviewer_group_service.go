@@ -0,0 +1,90 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"fmt"
+	"strings"
+)
+
+// SaveReviewerGroup creates the group or replaces the one with the same name.
+func (s *Service) SaveReviewerGroup(group *domain.ReviewerGroup) (*domain.ReviewerGroup, error) {
+	group.Labels = normalizeTags(group.Labels)
+	group.Members = trimNames(group.Members)
+	if group.Count == 0 {
+		group.Count = 1
+	}
+
+	if err := validateReviewerGroup(group); err != nil {
+		s.log.Debugf("invalid reviewer group %s: %v", group.Name, err)
+		return nil, errors.ErrInvalidReviewerGroup
+	}
+
+	if group.TeamName != "" {
+		exists, err := s.teamRepo.CheckExist(group.TeamName)
+		if err != nil {
+			s.log.Errorf("failed to check exist of team: %v", err)
+			return nil, err
+		}
+		if !exists {
+			s.log.Debugf("team with name: %s dosn't exist", group.TeamName)
+			return nil, errors.ErrNotFound
+		}
+	}
+
+	err := s.groupRepo.Save(group)
+	if err != nil {
+		s.log.Errorf("failed to save reviewer group: %v", err)
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *Service) GetReviewerGroups() ([]*domain.ReviewerGroup, error) {
+	groups, err := s.groupRepo.GetAll()
+	if err != nil {
+		s.log.Errorf("failed to get reviewer groups: %v", err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (s *Service) DeleteReviewerGroup(name string) error {
+	deleted, err := s.groupRepo.Delete(name)
+	if err != nil {
+		s.log.Errorf("failed to delete reviewer group: %v", err)
+		return err
+	}
+	if !deleted {
+		s.log.Debugf("reviewer group %s not found", name)
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func validateReviewerGroup(g *domain.ReviewerGroup) error {
+	if len(g.Members) == 0 {
+		return fmt.Errorf("members are empty")
+	}
+	if g.Count < 0 || g.Count > maxReviewerCount {
+		return fmt.Errorf("count must be between 1 and %d", maxReviewerCount)
+	}
+	return nil
+}
+
+func trimNames(names []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
+	"Pull-Requests-master/internal/slack"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAwayDays bounds the away period the /review command can set.
+const maxAwayDays = 90
+
+const slackUsage = "Команды: `/review mine` — мои открытые ревью, `/review reassign <pr>` — передать ревью, " +
+	"`/review away <дни>` — отсутствовать с сегодняшнего дня."
+
+// SlackConfig holds the Slack integration.
+type SlackConfig struct {
+	// Poster is nil when nothing is posted to team channels.
+	Poster slack.Poster
+	// Webhooks maps team names to the incoming webhook URL of their channel.
+	Webhooks map[string]string
+	// SigningSecret verifies slash commands; when empty every command is refused.
+	SigningSecret string
+}
+
+// HandleSlackCommand runs a /review slash command for the user whose Slack handle sent it.
+func (s *Service) HandleSlackCommand(timestamp string, signature string, body []byte) (*slack.Message, error) {
+	if !slack.Verify(s.cfg.Slack.SigningSecret, timestamp, signature, body, s.cfg.Clock.Now()) {
+		s.log.Debug("slack command signature doesn't match")
+		return nil, errors.ErrInvalidSignature
+	}
+	cmd, err := slack.ParseCommand(body)
+	if err != nil {
+		s.log.Debugf("invalid slack command: %v", err)
+		return nil, errors.APIError{Code: errors.ErrInvalidWebhook.Code, Message: err.Error()}
+	}
+
+	user, found, err := s.userRepo.GetBySlackHandle(cmd.UserID)
+	if err != nil {
+		s.log.Errorf("failed to get user by slack handle: %v", err)
+		return nil, err
+	}
+	if !found {
+		s.log.Debugf("slack user %s isn't linked", cmd.UserID)
+		return ephemeral(fmt.Sprintf("Slack-аккаунт не привязан: укажите %s в slack_handle своего профиля.", cmd.UserID)), nil
+	}
+
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 {
+		return ephemeral(slackUsage), nil
+	}
+	switch args[0] {
+	case "mine":
+		return s.slackMine(user)
+	case "reassign":
+		if len(args) != 2 {
+			return ephemeral("Укажите pull request: `/review reassign <pr>`."), nil
+		}
+		return s.slackReassign(user, args[1])
+	case "away":
+		days := 0
+		if len(args) == 2 {
+			days, _ = strconv.Atoi(args[1])
+		}
+		if days < 1 || days > maxAwayDays {
+			return ephemeral(fmt.Sprintf("Укажите число дней от 1 до %d: `/review away <дни>`.", maxAwayDays)), nil
+		}
+		return s.slackAway(user, days)
+	default:
+		return ephemeral(slackUsage), nil
+	}
+}
+
+func (s *Service) slackMine(user *domain.User) (*slack.Message, error) {
+	reviews, err := s.openReviews(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return ephemeral("Открытых ревью нет."), nil
+	}
+
+	lines := []string{}
+	for _, pr := range reviews {
+		lines = append(lines, fmt.Sprintf("• *%s* (`%s`)", slack.Escape(pr.Name), slack.Escape(pr.ID)))
+	}
+	return ephemeral("Ваши открытые ревью:\n" + strings.Join(lines, "\n")), nil
+}
+
+func (s *Service) slackReassign(user *domain.User, prID string) (*slack.Message, error) {
+	replacedBy, err := s.reassignOwn(user.ID, prID)
+	switch err {
+	case nil:
+	case errors.ErrNotFound:
+		return ephemeral(fmt.Sprintf("Pull request `%s` не найден.", slack.Escape(prID))), nil
+	case errors.ErrNotAssigned:
+		return ephemeral(fmt.Sprintf("Вы не ревьювер pull request `%s`.", slack.Escape(prID))), nil
+	case errors.ErrPRMerged:
+		return ephemeral(fmt.Sprintf("Pull request `%s` уже влит.", slack.Escape(prID))), nil
+	case errors.ErrPRClosed:
+		return ephemeral(fmt.Sprintf("Pull request `%s` закрыт.", slack.Escape(prID))), nil
+	default:
+		return nil, err
+	}
+	if replacedBy == "" {
+		return ephemeral("Замену найти не удалось, ревью осталось за вами."), nil
+	}
+	return ephemeral(fmt.Sprintf("Ревью `%s` передано %s.", slack.Escape(prID), s.slackName(replacedBy))), nil
+}
+
+func (s *Service) slackAway(user *domain.User, days int) (*slack.Message, error) {
+	now := s.cfg.Clock.Now()
+	period, err := s.AddAwayPeriod(&domain.AwayPeriod{
+		UserID:   user.ID,
+		StartsAt: now,
+		EndsAt:   now.Add(time.Duration(days) * 24 * time.Hour),
+		Reason:   "slack",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ephemeral(fmt.Sprintf("Вы отсутствуете до %s, новые ревью вам не назначаются.",
+		period.EndsAt.Format("02.01.2006 15:04 MST"))), nil
+}
+
+func ephemeral(text string) *slack.Message {
+	return &slack.Message{ResponseType: slack.ResponseEphemeral, Text: text}
+}
+
+// subscribeSlack posts new reviewers and reassignments to the channel of the author's team,
+// on a background worker once the change is committed.
+func (s *Service) subscribeSlack() {
+	if s.cfg.Slack.Poster == nil {
+		return
+	}
+
+	events.Subscribe(s.bus, func(ctx context.Context, e events.PRCreated) error {
+		if len(e.PR.AssignedReviewers) == 0 {
+			return nil
+		}
+		s.backgroundAfterCommit(ctx, "slack post", func() {
+			names := []string{}
+			for _, id := range e.PR.AssignedReviewers {
+				names = append(names, s.slackName(id))
+			}
+			s.postToSlack(e.PR.AuthorID, fmt.Sprintf("%s от %s: ревьюверы %s",
+				slackPR(&e.PR.PullRequestShort), s.slackName(e.PR.AuthorID), strings.Join(names, ", ")))
+		})
+		return nil
+	})
+	events.Subscribe(s.bus, func(ctx context.Context, e events.ReviewerReassigned) error {
+		s.backgroundAfterCommit(ctx, "slack post", func() {
+			pr, err := s.prRepo.GetByID(e.PullRequestID)
+			if err != nil {
+				s.log.Errorf("failed to get pr %s to post to slack: %v", e.PullRequestID, err)
+				return
+			}
+			text := fmt.Sprintf("%s: ревьювер %s заменён на %s",
+				slackPR(&pr.PullRequestShort), s.slackName(e.OldUserID), s.slackName(e.NewUserID))
+			if e.Actor != "" && e.Actor != e.OldUserID {
+				text += " по запросу " + s.slackName(e.Actor)
+			}
+			s.postToSlack(pr.AuthorID, text)
+		})
+		return nil
+	})
+}
+
+func slackPR(pr *domain.PullRequestShort) string {
+	return fmt.Sprintf("Pull request *%s* (`%s`)", slack.Escape(pr.Name), slack.Escape(pr.ID))
+}
+
+// slackName mentions the user by their Slack handle, or names them when they have none.
+func (s *Service) slackName(userID string) string {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return slack.Escape(userID)
+	}
+	if user.SlackHandle != "" {
+		return slack.Mention(user.SlackHandle)
+	}
+	return slack.Escape(user.Username)
+}
+
+// postToSlack posts to the channel of the user's team, if the team has one.
+func (s *Service) postToSlack(userID string, text string) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		s.log.Errorf("failed to get team of %s to post to slack: %v", userID, err)
+		return
+	}
+	url, ok := s.cfg.Slack.Webhooks[user.TeamName]
+	if !ok {
+		return
+	}
+	if err := s.cfg.Slack.Poster.Post(context.Background(), url, &slack.Message{Text: text}); err != nil {
+		s.log.Errorf("failed to post to slack channel of team %s: %v", user.TeamName, err)
+	}
+}
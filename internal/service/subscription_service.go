@@ -0,0 +1,261 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/outbound"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	webhookBatch       = 50
+	webhookMaxAttempts = 10
+	// deliveryLogLimit is how many deliveries of a subscription the log shows.
+	deliveryLogLimit = 100
+)
+
+// OutboundConfig holds how events are posted to webhook subscriptions.
+type OutboundConfig struct {
+	// Sender is nil when outbound webhooks are off.
+	Sender outbound.Sender
+	// MaxAttempts defaults to webhookMaxAttempts.
+	MaxAttempts int
+}
+
+func (s *Service) CreateSubscription(sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	sub.Events = trimNames(sub.Events)
+	if err := validateSubscription(sub); err != nil {
+		s.log.Debugf("invalid subscription: %v", err)
+		return nil, errors.APIError{Code: errors.ErrInvalidSubscription.Code, Message: err.Error()}
+	}
+
+	newSub, err := s.subscriptionRepo.Create(sub)
+	if err != nil {
+		s.log.Errorf("failed to create subscription: %v", err)
+		return nil, err
+	}
+
+	newSub.Secret = ""
+	return newSub, nil
+}
+
+func (s *Service) GetSubscriptions() ([]*domain.WebhookSubscription, error) {
+	subs, err := s.subscriptionRepo.GetAll()
+	if err != nil {
+		s.log.Errorf("failed to get subscriptions: %v", err)
+		return nil, err
+	}
+
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *Service) DeleteSubscription(id int64) error {
+	deleted, err := s.subscriptionRepo.Delete(id)
+	if err != nil {
+		s.log.Errorf("failed to delete subscription: %v", err)
+		return err
+	}
+	if !deleted {
+		s.log.Debugf("subscription with id: %d not found", id)
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the latest deliveries of the subscription, optionally only those
+// with the given status.
+func (s *Service) GetDeliveries(subscriptionID int64, status string) ([]*domain.WebhookDelivery, error) {
+	_, found, err := s.subscriptionRepo.Get(subscriptionID)
+	if err != nil {
+		s.log.Errorf("failed to get subscription: %v", err)
+		return nil, err
+	}
+	if !found {
+		s.log.Debugf("subscription with id: %d not found", subscriptionID)
+		return nil, errors.ErrNotFound
+	}
+
+	deliveries, err := s.deliveryRepo.GetBySubscription(subscriptionID, status, deliveryLogLimit)
+	if err != nil {
+		s.log.Errorf("failed to get deliveries: %v", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDelivery queues a failed delivery again, with a fresh set of retries, and wakes the
+// retrier to send it. The delivery is returned pending.
+func (s *Service) ReplayDelivery(id int64) (*domain.WebhookDelivery, error) {
+	if s.cfg.Outbound.Sender == nil {
+		s.log.Debug("outbound webhooks are disabled")
+		return nil, errors.ErrOutboundDisabled
+	}
+
+	delivery, found, err := s.deliveryRepo.Get(id)
+	if err != nil {
+		s.log.Errorf("failed to get delivery: %v", err)
+		return nil, err
+	}
+	if !found {
+		s.log.Debugf("delivery with id: %d not found", id)
+		return nil, errors.ErrNotFound
+	}
+	if delivery.Status != domain.JobFailed {
+		s.log.Debugf("delivery with id: %d is %s", id, delivery.Status)
+		return nil, errors.ErrDeliveryNotFailed
+	}
+
+	_, found, err = s.subscriptionRepo.Get(delivery.SubscriptionID)
+	if err != nil {
+		s.log.Errorf("failed to get subscription: %v", err)
+		return nil, err
+	}
+	if !found {
+		s.log.Debugf("subscription with id: %d not found", delivery.SubscriptionID)
+		return nil, errors.ErrNotFound
+	}
+
+	delivery, err = s.deliveryRepo.Replay(id, s.cfg.Clock.Now())
+	if err != nil {
+		s.log.Errorf("failed to replay delivery: %v", err)
+		return nil, err
+	}
+	if delivery == nil {
+		s.log.Debugf("delivery with id: %d is no longer failed", id)
+		return nil, errors.ErrDeliveryNotFailed
+	}
+
+	s.whenCommitted(func(s *Service) { wake(s.deliveriesQueued) })
+	return delivery, nil
+}
+
+// ProcessWebhookDeliveries delivers the deliveries due by now.
+func (s *Service) ProcessWebhookDeliveries(now time.Time) error {
+	if s.cfg.Outbound.Sender == nil {
+		return nil
+	}
+
+	deliveries, err := s.deliveryRepo.GetDue(now, webhookBatch)
+	if err != nil {
+		s.log.Errorf("failed to get due webhook deliveries: %v", err)
+		return err
+	}
+
+	subs := map[int64]*domain.WebhookSubscription{}
+	for _, delivery := range deliveries {
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, _, err = s.subscriptionRepo.Get(delivery.SubscriptionID)
+			if err != nil {
+				s.log.Errorf("failed to get subscription: %v", err)
+				return err
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		if sub != nil {
+			s.deliver(sub, delivery)
+		}
+	}
+	return nil
+}
+
+// publish queues the event for every subscription that asked for it and wakes the
+// retrier once the change is committed, so subscribers are never called while a request
+// waits.
+func (s *Service) publish(event string, data interface{}) error {
+	if s.cfg.Outbound.Sender == nil {
+		return nil
+	}
+
+	subs, err := s.subscriptionRepo.GetByEvent(event)
+	if err != nil {
+		s.log.Errorf("failed to get subscriptions of %s: %v", event, err)
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(domain.EventPayload{Event: event, OccurredAt: s.cfg.Clock.Now(), Data: data})
+	if err != nil {
+		s.log.Errorf("failed to marshal %s payload: %v", event, err)
+		return err
+	}
+	for _, sub := range subs {
+		_, err := s.deliveryRepo.Enqueue(&domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			Event:          event,
+			Payload:        payload,
+		})
+		if err != nil {
+			s.log.Errorf("failed to queue %s for subscription %d: %v", event, sub.ID, err)
+			return err
+		}
+	}
+	s.whenCommitted(func(s *Service) { wake(s.deliveriesQueued) })
+	return nil
+}
+
+// deliver makes one attempt at the delivery, then marks it done, schedules the next
+// attempt with exponential backoff or gives up after the last one.
+func (s *Service) deliver(sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) {
+	status, err := s.cfg.Outbound.Sender.Send(context.Background(), sub, delivery)
+	attempts := delivery.Attempts + 1
+
+	if err == nil {
+		if err := s.deliveryRepo.MarkDone(delivery.ID, attempts, status); err != nil {
+			s.log.Errorf("failed to mark webhook delivery as done: %v", err)
+		}
+		return
+	}
+
+	maxAttempts := s.cfg.Outbound.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = webhookMaxAttempts
+	}
+	if attempts >= maxAttempts {
+		s.log.Errorf("giving up on webhook delivery %d after %d attempts: %v", delivery.ID, attempts, err)
+		if err := s.deliveryRepo.MarkFailed(delivery.ID, attempts, status, err.Error()); err != nil {
+			s.log.Errorf("failed to mark webhook delivery as failed: %v", err)
+		}
+		return
+	}
+
+	backoff := retryBackoff(attempts)
+	s.log.Infof("webhook delivery %d failed, retrying in %s: %v", delivery.ID, backoff, err)
+	if err := s.deliveryRepo.Reschedule(delivery.ID, attempts, s.cfg.Clock.Now().Add(backoff), status, err.Error()); err != nil {
+		s.log.Errorf("failed to reschedule webhook delivery: %v", err)
+	}
+}
+
+func validateSubscription(sub *domain.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) url")
+	}
+	if sub.Secret == "" {
+		return fmt.Errorf("secret is required to sign deliveries")
+	}
+	if len(sub.Events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, event := range sub.Events {
+		known := false
+		for _, e := range domain.WebhookEvents {
+			known = known || e == event
+		}
+		if !known {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
+	"time"
 )
 
 func (s *Service) CreateTeam(team *domain.Team) (*domain.Team, error) {
@@ -17,23 +19,40 @@ func (s *Service) CreateTeam(team *domain.Team) (*domain.Team, error) {
 		return nil, errors.ErrTeamExists
 	}
 
-	newTeam, err := s.teamRepo.Create(team)
-	if err != nil {
-		s.log.Errorf("failed to create team: %v", err)
-		return nil, err
-	}
+	var newTeam *domain.Team
+	err = s.inTx(func(tx *Service) error {
+		newTeam, err = tx.teamRepo.Create(team)
+		if err != nil {
+			tx.log.Errorf("failed to create team: %v", err)
+			return err
+		}
 
-	for i := 0; i < len(team.Members); i++ {
-		user := domain.User{
-			Member:   *team.Members[i],
-			TeamName: team.Name,
+		for i := 0; i < len(team.Members); i++ {
+			user := domain.User{
+				Member:   *team.Members[i],
+				TeamName: team.Name,
+			}
+			newUser, err := tx.CreateUser(&user)
+			if err != nil {
+				tx.log.Errorf("failed to create user: %v", err)
+				return err
+			}
+			newTeam.Members = append(newTeam.Members, &newUser.Member)
 		}
-		newUser, err := s.CreateUser(&user)
-		if err != nil {
-			s.log.Errorf("failed to create user: %v", err)
-			return nil, err
+
+		if team.Policy != nil {
+			err = tx.teamRepo.SetPolicy(team.Name, team.Policy)
+			if err != nil {
+				tx.log.Errorf("failed to set team policy: %v", err)
+				return err
+			}
+			newTeam.Policy = team.Policy
 		}
-		newTeam.Members = append(newTeam.Members, &newUser.Member)
+
+		return tx.raise(events.TeamChanged{Team: newTeam})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return newTeam, nil
@@ -57,5 +76,116 @@ func (s *Service) GetTeamByName(teamName string) (*domain.Team, error) {
 		return nil, err
 	}
 
+	newTeam.Policy, err = s.teamRepo.GetPolicy(teamName)
+	if err != nil {
+		s.log.Errorf("failed to get team policy: %v", err)
+		return nil, err
+	}
+
 	return newTeam, nil
 }
+
+func (s *Service) SetTeamPolicy(teamName string, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	exists, err := s.teamRepo.CheckExist(teamName)
+	if err != nil {
+		s.log.Errorf("failed to check exist of team: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("team with name: %s dosn't exist", teamName)
+		return nil, errors.ErrNotFound
+	}
+
+	err = s.inTx(func(tx *Service) error {
+		err := tx.teamRepo.SetPolicy(teamName, policy)
+		if err != nil {
+			tx.log.Errorf("failed to set team policy: %v", err)
+			return err
+		}
+		team, err := tx.teamRepo.GetByName(teamName)
+		if err != nil {
+			tx.log.Errorf("failed to get team by name: %v", err)
+			return err
+		}
+		team.Policy = policy
+		return tx.raise(events.TeamChanged{Team: team})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *Service) ImportHolidays(teamName string, holidays []*domain.Holiday) ([]*domain.Holiday, error) {
+	exists, err := s.teamRepo.CheckExist(teamName)
+	if err != nil {
+		s.log.Errorf("failed to check exist of team: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("team with name: %s dosn't exist", teamName)
+		return nil, errors.ErrNotFound
+	}
+
+	for _, holiday := range holidays {
+		holiday.TeamName = teamName
+	}
+
+	err = s.teamRepo.AddHolidays(teamName, holidays)
+	if err != nil {
+		s.log.Errorf("failed to add holidays: %v", err)
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+func (s *Service) GetHolidays(teamName string, from string) ([]*domain.Holiday, error) {
+	exists, err := s.teamRepo.CheckExist(teamName)
+	if err != nil {
+		s.log.Errorf("failed to check exist of team: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("team with name: %s dosn't exist", teamName)
+		return nil, errors.ErrNotFound
+	}
+
+	holidays, err := s.teamRepo.GetHolidays([]string{teamName}, from)
+	if err != nil {
+		s.log.Errorf("failed to get holidays: %v", err)
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+// GetPairings returns who reviewed whom in the team since the given time; a zero time
+// means the pairing window.
+func (s *Service) GetPairings(teamName string, since time.Time) ([]*domain.Pairing, error) {
+	exists, err := s.teamRepo.CheckExist(teamName)
+	if err != nil {
+		s.log.Errorf("failed to check exist of team: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		s.log.Debugf("team with name: %s dosn't exist", teamName)
+		return nil, errors.ErrNotFound
+	}
+
+	if since.IsZero() {
+		since = s.cfg.Clock.Now().Add(-s.cfg.PairingWindow)
+	}
+	pairings, err := s.prRepo.GetPairings(teamName, since)
+	if err != nil {
+		s.log.Errorf("failed to get pairings: %v", err)
+		return nil, err
+	}
+
+	return pairings, nil
+}
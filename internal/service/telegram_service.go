@@ -0,0 +1,209 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/notify"
+	"Pull-Requests-master/internal/telegram"
+	"fmt"
+	"strings"
+)
+
+const telegramUsage = "Команды:\n" +
+	"/link <user_id> — привязать аккаунт (telegram_handle профиля должен совпадать с вашим логином)\n" +
+	"/unlink — отвязать аккаунт\n" +
+	"/reviews — мои открытые ревью\n" +
+	"/active [on|off] — переключить, назначают ли мне ревью\n" +
+	"/reassign <pr> — передать ревью другому"
+
+const telegramFailed = "Не получилось, попробуйте позже."
+
+// TelegramConfig holds the Telegram bot.
+type TelegramConfig struct {
+	// Sender is nil when the bot is off; otherwise notifications can go to linked chats.
+	Sender notify.TelegramSender
+}
+
+// addTelegramChannel lets notifications go to the chats users linked with the bot.
+func (s *Service) addTelegramChannel() {
+	if s.cfg.Telegram.Sender == nil {
+		return
+	}
+	channels := map[string]notify.Channel{}
+	for name, channel := range s.cfg.Notify.Channels {
+		channels[name] = channel
+	}
+	channels[domain.ChannelTelegram] = notify.NewTelegram(s.cfg.Telegram.Sender, s.telegramRepo.GetChatID)
+	s.cfg.Notify.Channels = channels
+}
+
+// HandleTelegramMessage answers a message to the bot. Commands other than /link only work
+// in the private chat a user linked.
+func (s *Service) HandleTelegramMessage(msg *telegram.Message) string {
+	command, args, ok := telegram.ParseCommand(msg.Text)
+	if !ok || command == "start" || command == "help" {
+		return telegramUsage
+	}
+	if msg.Chat.Type != telegram.ChatPrivate || msg.From == nil {
+		return "Напишите мне в личные сообщения."
+	}
+
+	switch command {
+	case "link":
+		if len(args) != 1 {
+			return "Укажите свой user_id: /link <user_id>"
+		}
+		return s.telegramLink(msg, args[0])
+	case "unlink":
+		return s.telegramUnlink(msg.Chat.ID)
+	}
+
+	userID, found, err := s.telegramRepo.GetUserID(msg.Chat.ID)
+	if err != nil {
+		s.log.Errorf("failed to get user of telegram chat: %v", err)
+		return telegramFailed
+	}
+	if !found {
+		return "Сначала привяжите аккаунт: /link <user_id>"
+	}
+
+	switch command {
+	case "reviews":
+		return s.telegramReviews(userID)
+	case "active":
+		return s.telegramActive(userID, args)
+	case "reassign":
+		if len(args) != 1 {
+			return "Укажите pull request: /reassign <pr>"
+		}
+		return s.telegramReassign(userID, args[0])
+	default:
+		return telegramUsage
+	}
+}
+
+// telegramLink links the chat to the user whose profile names the sender's Telegram login,
+// and turns on Telegram notifications for them.
+func (s *Service) telegramLink(msg *telegram.Message, userID string) string {
+	user, err := s.GetUser(userID)
+	if err == errors.ErrNotFound {
+		return fmt.Sprintf("Пользователь %s не найден.", userID)
+	}
+	if err != nil {
+		return telegramFailed
+	}
+	handle := strings.TrimPrefix(user.TelegramHandle, "@")
+	if handle == "" || !strings.EqualFold(handle, msg.From.Username) {
+		return fmt.Sprintf("Укажите свой логин Telegram в telegram_handle профиля %s и повторите /link.", userID)
+	}
+
+	linked, found, err := s.telegramRepo.GetUserID(msg.Chat.ID)
+	if err != nil {
+		s.log.Errorf("failed to get user of telegram chat: %v", err)
+		return telegramFailed
+	}
+	if found && linked != userID {
+		return fmt.Sprintf("Этот чат уже привязан к %s, сначала выполните /unlink.", linked)
+	}
+
+	err = s.inTx(func(tx *Service) error {
+		if err := tx.telegramRepo.Link(userID, msg.Chat.ID); err != nil {
+			tx.log.Errorf("failed to link telegram chat: %v", err)
+			return err
+		}
+		prefs, err := tx.preferences(userID)
+		if err != nil {
+			return err
+		}
+		if contains(prefs.Channels, domain.ChannelTelegram) {
+			return nil
+		}
+		prefs.Channels = append(prefs.Channels, domain.ChannelTelegram)
+		if _, err := tx.notificationRepo.SavePreferences(prefs); err != nil {
+			tx.log.Errorf("failed to save notification preferences: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return telegramFailed
+	}
+	return fmt.Sprintf("Аккаунт %s привязан, уведомления о ревью будут приходить сюда.", userID)
+}
+
+func (s *Service) telegramUnlink(chatID int64) string {
+	unlinked, err := s.telegramRepo.Unlink(chatID)
+	if err != nil {
+		s.log.Errorf("failed to unlink telegram chat: %v", err)
+		return telegramFailed
+	}
+	if !unlinked {
+		return "Этот чат не привязан."
+	}
+	return "Аккаунт отвязан."
+}
+
+func (s *Service) telegramReviews(userID string) string {
+	reviews, err := s.openReviews(userID)
+	if err != nil {
+		return telegramFailed
+	}
+	if len(reviews) == 0 {
+		return "Открытых ревью нет."
+	}
+
+	lines := []string{"Ваши открытые ревью:"}
+	for _, pr := range reviews {
+		lines = append(lines, fmt.Sprintf("• %s (%s)", pr.Name, pr.ID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// telegramActive toggles whether the user gets reviews, or sets it with on or off.
+func (s *Service) telegramActive(userID string, args []string) string {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return telegramFailed
+	}
+
+	active := !user.IsActive
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			active = true
+		case "off":
+			active = false
+		default:
+			return "Используйте /active, /active on или /active off"
+		}
+	}
+
+	if _, err := s.SetUserActive(userID, active); err != nil {
+		return telegramFailed
+	}
+	if active {
+		return "Вам снова назначают ревью."
+	}
+	return "Вам больше не назначают ревью. Включить обратно: /active on"
+}
+
+func (s *Service) telegramReassign(userID string, prID string) string {
+	replacedBy, err := s.reassignOwn(userID, prID)
+	switch err {
+	case nil:
+	case errors.ErrNotFound:
+		return fmt.Sprintf("Pull request %s не найден.", prID)
+	case errors.ErrNotAssigned:
+		return fmt.Sprintf("Вы не ревьювер pull request %s.", prID)
+	case errors.ErrPRMerged:
+		return fmt.Sprintf("Pull request %s уже влит.", prID)
+	case errors.ErrPRClosed:
+		return fmt.Sprintf("Pull request %s закрыт.", prID)
+	default:
+		return telegramFailed
+	}
+	if replacedBy == "" {
+		return "Замену найти не удалось, ревью осталось за вами."
+	}
+	return fmt.Sprintf("Ревью %s передано %s.", prID, replacedBy)
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"Pull-Requests-master/internal/assignment"
 	"Pull-Requests-master/internal/domain"
 	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
 )
 
 func (s *Service) CreateUser(user *domain.User) (*domain.User, error) {
@@ -30,7 +36,30 @@ func (s *Service) CreateUser(user *domain.User) (*domain.User, error) {
 }
 
 func (s *Service) SetUserActive(id string, status bool) (*domain.User, error) {
+	user, err := s.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+
 	var newUser *domain.User
+	err = s.inTx(func(tx *Service) error {
+		newUser, err = tx.userRepo.SetUserActive(id, status)
+		if err != nil {
+			tx.log.Errorf("failed to set user active: %v", err)
+			return err
+		}
+		if user.IsActive == status {
+			return nil
+		}
+		return tx.raise(events.UserActivityChanged{User: newUser, WasActive: user.IsActive})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newUser, nil
+}
+
+func (s *Service) GetUserReviews(id string) ([]*domain.PullRequestShort, error) {
 	exists, err := s.userRepo.CheckExist(id)
 	if err != nil {
 		s.log.Errorf("failed to check exist of user: %v", err)
@@ -41,15 +70,32 @@ func (s *Service) SetUserActive(id string, status bool) (*domain.User, error) {
 		return nil, errors.ErrNotFound
 	}
 
-	newUser, err = s.userRepo.SetUserActive(id, status)
+	pullRequests, err := s.userRepo.GetReview(id)
 	if err != nil {
-		s.log.Errorf("failed to set user active: %v", err)
+		s.log.Errorf("failed to get review: %v", err)
 		return nil, err
 	}
-	return newUser, nil
+
+	return pullRequests, nil
 }
 
-func (s *Service) GetUserReviews(id string) ([]*domain.PullRequestShort, error) {
+// openReviews returns the open pull requests the user reviews, for the chat commands.
+func (s *Service) openReviews(id string) ([]*domain.PullRequestShort, error) {
+	reviews, err := s.GetUserReviews(id)
+	if err != nil {
+		return nil, err
+	}
+
+	open := []*domain.PullRequestShort{}
+	for _, pr := range reviews {
+		if pr.Status == "OPEN" {
+			open = append(open, pr)
+		}
+	}
+	return open, nil
+}
+
+func (s *Service) GetUser(id string) (*domain.User, error) {
 	exists, err := s.userRepo.CheckExist(id)
 	if err != nil {
 		s.log.Errorf("failed to check exist of user: %v", err)
@@ -60,11 +106,115 @@ func (s *Service) GetUserReviews(id string) ([]*domain.PullRequestShort, error)
 		return nil, errors.ErrNotFound
 	}
 
-	pullRequests, err := s.userRepo.GetReview(id)
+	user, err := s.userRepo.GetByID(id)
 	if err != nil {
-		s.log.Errorf("failed to get review: %v", err)
+		s.log.Errorf("failed to get user by id: %v", err)
 		return nil, err
 	}
 
-	return pullRequests, nil
+	return user, nil
+}
+
+func (s *Service) UpdateUserProfile(upd *domain.ProfileUpdate) (*domain.User, error) {
+	user, err := s.GetUser(upd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Email != nil {
+		user.Email = strings.TrimSpace(*upd.Email)
+	}
+	if upd.SlackHandle != nil {
+		user.SlackHandle = strings.TrimPrefix(strings.TrimSpace(*upd.SlackHandle), "@")
+	}
+	if upd.TelegramHandle != nil {
+		user.TelegramHandle = strings.TrimPrefix(strings.TrimSpace(*upd.TelegramHandle), "@")
+	}
+	if upd.TimeZone != nil {
+		user.TimeZone = strings.TrimSpace(*upd.TimeZone)
+	}
+	if upd.Seniority != nil {
+		user.Seniority = strings.ToLower(strings.TrimSpace(*upd.Seniority))
+	}
+	if upd.Skills != nil {
+		user.Skills = normalizeTags(*upd.Skills)
+	}
+	if upd.WorkStart != nil {
+		user.WorkStart = strings.TrimSpace(*upd.WorkStart)
+	}
+	if upd.WorkEnd != nil {
+		user.WorkEnd = strings.TrimSpace(*upd.WorkEnd)
+	}
+	if upd.WorkDays != nil {
+		user.WorkDays = *upd.WorkDays
+	}
+	if user.Skills == nil {
+		user.Skills = []string{}
+	}
+	if user.WorkDays == nil {
+		user.WorkDays = []int64{}
+	}
+
+	if err := validateProfile(&user.Profile); err != nil {
+		s.log.Debugf("invalid profile of user %s: %v", user.ID, err)
+		return nil, errors.ErrInvalidProfile
+	}
+
+	newUser, err := s.userRepo.UpdateProfile(user)
+	if err != nil {
+		s.log.Errorf("failed to update user profile: %v", err)
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+func validateProfile(p *domain.Profile) error {
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return fmt.Errorf("bad email %q: %v", p.Email, err)
+		}
+	}
+	if p.TimeZone != "" {
+		if _, err := time.LoadLocation(p.TimeZone); err != nil {
+			return fmt.Errorf("bad time zone %q: %v", p.TimeZone, err)
+		}
+	}
+	if (p.WorkStart == "") != (p.WorkEnd == "") {
+		return fmt.Errorf("work_start and work_end must be set together")
+	}
+	if p.WorkStart != "" {
+		if _, err := assignment.ParseClock(p.WorkStart); err != nil {
+			return err
+		}
+		if _, err := assignment.ParseClock(p.WorkEnd); err != nil {
+			return err
+		}
+	}
+	for _, day := range p.WorkDays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("bad work day %d", day)
+		}
+	}
+	switch p.Seniority {
+	case "", domain.SeniorityJunior, domain.SeniorityMiddle, domain.SenioritySenior:
+	default:
+		return fmt.Errorf("bad seniority %q", p.Seniority)
+	}
+	return nil
+}
+
+// normalizeTags lowercases tags, drops empty ones and duplicates, keeping the original order.
+func normalizeTags(tags []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
 }
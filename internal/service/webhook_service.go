@@ -0,0 +1,176 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"Pull-Requests-master/internal/errors"
+	"Pull-Requests-master/internal/events"
+	"Pull-Requests-master/internal/webhook"
+)
+
+// WebhookConfig holds the settings of a code host webhook.
+type WebhookConfig struct {
+	// Secret signs (GitHub) or comes with (GitLab) the deliveries; when empty every
+	// delivery is refused.
+	Secret string
+	// Users maps code host logins to user ids; unmapped logins are taken as user ids.
+	Users map[string]string
+	// UserIDs maps numeric code host user ids to user ids, for authors an event names
+	// only by id.
+	UserIDs map[int]string
+}
+
+// HandleGitHubWebhook applies a GitHub delivery. It returns the action taken and the pull
+// request it was taken on, nil when the delivery was ignored.
+func (s *Service) HandleGitHubWebhook(eventType string, signature string, body []byte) (string, *domain.PullRequest, error) {
+	if !webhook.VerifyGitHub(s.cfg.GitHub.Secret, body, signature) {
+		s.log.Debug("github webhook signature doesn't match")
+		return "", nil, errors.ErrInvalidSignature
+	}
+	if eventType == "pull_request_review" {
+		parsed, err := webhook.ParseGitHubReview(body)
+		if err != nil {
+			s.log.Debugf("invalid github webhook: %v", err)
+			return "", nil, errors.APIError{Code: errors.ErrInvalidWebhook.Code, Message: err.Error()}
+		}
+		return s.applyReview(parsed.Verdict(), s.cfg.GitHub.Users)
+	}
+	if eventType != "pull_request" {
+		s.log.Debugf("github %s event ignored", eventType)
+		return webhook.ActionIgnore, nil, nil
+	}
+
+	parsed, err := webhook.ParseGitHub(body)
+	if err != nil {
+		s.log.Debugf("invalid github webhook: %v", err)
+		return "", nil, errors.APIError{Code: errors.ErrInvalidWebhook.Code, Message: err.Error()}
+	}
+	return s.applyEvent(parsed.Event(), s.cfg.GitHub)
+}
+
+// HandleGitLabWebhook applies a GitLab delivery, like HandleGitHubWebhook.
+func (s *Service) HandleGitLabWebhook(eventType string, token string, body []byte) (string, *domain.PullRequest, error) {
+	if !webhook.VerifyGitLab(s.cfg.GitLab.Secret, token) {
+		s.log.Debug("gitlab webhook token doesn't match")
+		return "", nil, errors.ErrInvalidSignature
+	}
+	if eventType != "Merge Request Hook" {
+		s.log.Debugf("gitlab %s event ignored", eventType)
+		return webhook.ActionIgnore, nil, nil
+	}
+
+	parsed, err := webhook.ParseGitLab(body)
+	if err != nil {
+		s.log.Debugf("invalid gitlab webhook: %v", err)
+		return "", nil, errors.APIError{Code: errors.ErrInvalidWebhook.Code, Message: err.Error()}
+	}
+	return s.applyEvent(parsed.Event(), s.cfg.GitLab)
+}
+
+// applyReview records the reviewer's verdict on the pull request, whether they were
+// assigned or not. Reviews of unknown pull requests or by unknown users are ignored.
+func (s *Service) applyReview(review *webhook.Review, users map[string]string) (string, *domain.PullRequest, error) {
+	if review.Verdict == "" {
+		return webhook.ActionIgnore, nil, nil
+	}
+	if id, ok := users[review.Reviewer]; ok {
+		review.Reviewer = id
+	}
+
+	exists, err := s.prRepo.CheckPRExist(review.PullRequestID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of pr: %v", err)
+		return "", nil, err
+	}
+	if !exists {
+		s.log.Debugf("review of unknown pr %s ignored", review.PullRequestID)
+		return webhook.ActionIgnore, nil, nil
+	}
+
+	exists, err = s.userRepo.CheckExist(review.Reviewer)
+	if err != nil {
+		s.log.Errorf("failed to check exist of user: %v", err)
+		return "", nil, err
+	}
+	if !exists {
+		s.log.Debugf("review of pr %s by unknown user %s ignored", review.PullRequestID, review.Reviewer)
+		return webhook.ActionIgnore, nil, nil
+	}
+
+	at := review.SubmittedAt
+	if at.IsZero() {
+		at = s.cfg.Clock.Now()
+	}
+	err = s.inTx(func(tx *Service) error {
+		if err := tx.prRepo.RecordVerdict(review.PullRequestID, review.Reviewer, review.Verdict, at); err != nil {
+			tx.log.Errorf("failed to record verdict: %v", err)
+			return err
+		}
+		return tx.raise(events.ReviewSubmitted{
+			PullRequestID: review.PullRequestID,
+			UserID:        review.Reviewer,
+			Verdict:       review.Verdict,
+			At:            at,
+		})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	pr, err := s.prRepo.GetByID(review.PullRequestID)
+	if err != nil {
+		s.log.Errorf("failed to get pr by id: %v", err)
+		return "", nil, err
+	}
+	return webhook.ActionReview, pr, nil
+}
+
+// applyEvent drives the pull request operations from a code host event. Pull requests
+// the service never saw are created when they open and left alone when they close.
+func (s *Service) applyEvent(event *webhook.Event, cfg WebhookConfig) (string, *domain.PullRequest, error) {
+	if event.PR.AuthorID == "" {
+		id, ok := cfg.UserIDs[event.AuthorHostID]
+		if !ok {
+			s.log.Debugf("author %d of pr %s isn't mapped to a user", event.AuthorHostID, event.PR.ID)
+		}
+		event.PR.AuthorID = id
+	} else if id, ok := cfg.Users[event.PR.AuthorID]; ok {
+		event.PR.AuthorID = id
+	}
+	for i, login := range event.PR.CoAuthorIDs {
+		if id, ok := cfg.Users[login]; ok {
+			event.PR.CoAuthorIDs[i] = id
+		}
+	}
+	if event.Action == webhook.ActionIgnore {
+		return webhook.ActionIgnore, nil, nil
+	}
+
+	exists, err := s.prRepo.CheckPRExist(event.PR.ID)
+	if err != nil {
+		s.log.Errorf("failed to check exist of pr: %v", err)
+		return "", nil, err
+	}
+
+	var pr *domain.PullRequest
+	switch {
+	case !exists && (event.Action == webhook.ActionOpen || event.Action == webhook.ActionReopen):
+		pr, err = s.CreatePR(&event.PR)
+		return webhook.ActionOpen, pr, err
+	case !exists:
+		s.log.Debugf("%s of unknown pr %s ignored", event.Action, event.PR.ID)
+		return webhook.ActionIgnore, nil, nil
+	case event.Action == webhook.ActionOpen:
+		s.log.Debugf("pr %s is already open", event.PR.ID)
+		return webhook.ActionIgnore, nil, nil
+	case event.Action == webhook.ActionReopen:
+		pr, err = s.ReopenPR(event.PR.ID)
+	case event.Action == webhook.ActionMerge:
+		pr, err = s.merge(event.PR.ID, false)
+	case event.Action == webhook.ActionClose:
+		pr, err = s.ClosePR(event.PR.ID)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return event.Action, pr, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"Pull-Requests-master/internal/events"
+	"context"
+)
+
+const (
+	// backgroundWorkers send notifications and Slack posts, which have no queue to retry
+	// them; backgroundQueue calls may wait for a worker before new ones are dropped.
+	backgroundWorkers = 4
+	backgroundQueue   = 256
+)
+
+// startWorkers starts the workers of inBackground.
+func (s *Service) startWorkers() {
+	s.background = make(chan func(), backgroundQueue)
+	for i := 0; i < backgroundWorkers; i++ {
+		go func() {
+			for f := range s.background {
+				f()
+			}
+		}()
+	}
+}
+
+// inBackground runs f on a worker, so a slow mail server or chat API never holds up the
+// request that caused the call. Calls are dropped while all workers are busy and the
+// queue is full.
+func (s *Service) inBackground(what string, f func()) {
+	select {
+	case s.background <- f:
+	default:
+		s.log.Errorf("too many calls waiting, dropped %s", what)
+	}
+}
+
+// CodeHostQueued is signalled when reviewer changes are queued for the code host.
+func (s *Service) CodeHostQueued() <-chan struct{} {
+	return s.codeHostQueued
+}
+
+// DeliveriesQueued is signalled when webhook deliveries are queued.
+func (s *Service) DeliveriesQueued() <-chan struct{} {
+	return s.deliveriesQueued
+}
+
+// wake signals the runner of a queue without waiting for it; a signal that is already
+// pending covers the new work too.
+func wake(queued chan struct{}) {
+	select {
+	case queued <- struct{}{}:
+	default:
+	}
+}
+
+// backgroundAfterCommit runs f on a worker once the change that raised the event is
+// committed.
+func (s *Service) backgroundAfterCommit(ctx context.Context, what string, f func()) {
+	events.AfterCommit(ctx, func() { s.inBackground(what, f) })
+}
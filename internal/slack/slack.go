@@ -0,0 +1,147 @@
+// Package slack posts review messages to team channels through incoming webhooks and
+// takes the /review slash command.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers Slack signs its requests with. The signature is "v0=" followed by the hex
+// HMAC of "v0:<timestamp>:<body>" keyed with the app's signing secret.
+const (
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature = "X-Slack-Signature"
+)
+
+// maxSkew is how old a signed request may be before it is taken for a replay.
+const maxSkew = 5 * time.Minute
+
+// ResponseEphemeral shows a command response only to the user who ran the command.
+const ResponseEphemeral = "ephemeral"
+
+// Message is the body of an incoming webhook post or of a command response.
+type Message struct {
+	ResponseType string `json:"response_type,omitempty"`
+	Text         string `json:"text"`
+}
+
+// Poster posts a message to an incoming webhook URL.
+type Poster interface {
+	Post(ctx context.Context, url string, msg *Message) error
+}
+
+// HTTP is the Poster used in production.
+type HTTP struct {
+	client *http.Client
+}
+
+func NewHTTP(timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTP{client: &http.Client{Timeout: timeout}}
+}
+
+func (h *HTTP) Post(ctx context.Context, url string, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Sign returns the signature header value of a request sent at timestamp.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a request and that it was sent around now.
+func Verify(secret string, timestamp string, signature string, body []byte, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := now.Sub(time.Unix(sec, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Command is a slash command invocation.
+type Command struct {
+	Command string
+	Text    string
+	UserID  string
+	TeamID  string
+}
+
+// ParseCommand reads the form Slack posts a slash command as.
+func ParseCommand(body []byte) (*Command, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	cmd := &Command{
+		Command: form.Get("command"),
+		Text:    strings.TrimSpace(form.Get("text")),
+		UserID:  form.Get("user_id"),
+		TeamID:  form.Get("team_id"),
+	}
+	if cmd.UserID == "" {
+		return nil, fmt.Errorf("user_id is missing")
+	}
+	return cmd, nil
+}
+
+var memberID = regexp.MustCompile(`^[UW][A-Z0-9]{2,}$`)
+
+// Mention formats a Slack handle so that the user is notified: member ids become
+// <@U123>, names are written as @name.
+func Mention(handle string) string {
+	handle = strings.TrimPrefix(handle, "@")
+	if memberID.MatchString(handle) {
+		return "<@" + handle + ">"
+	}
+	return "@" + handle
+}
+
+// Escape keeps text from being read as Slack markup.
+func Escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,99 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The example request of Slack's "Verifying requests from Slack" guide.
+const (
+	exampleSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	exampleTimestamp = "1531420618"
+	exampleBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	exampleSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+func TestVerify(t *testing.T) {
+	sent := time.Unix(1531420618, 0)
+
+	assert.Equal(t, exampleSignature, Sign(exampleSecret, exampleTimestamp, []byte(exampleBody)))
+	assert.True(t, Verify(exampleSecret, exampleTimestamp, exampleSignature, []byte(exampleBody), sent.Add(time.Minute)))
+
+	assert.False(t, Verify(exampleSecret, exampleTimestamp, exampleSignature, []byte(exampleBody+"x"), sent))
+	assert.False(t, Verify("other", exampleTimestamp, exampleSignature, []byte(exampleBody), sent))
+	assert.False(t, Verify("", exampleTimestamp, exampleSignature, []byte(exampleBody), sent))
+	assert.False(t, Verify(exampleSecret, "yesterday", exampleSignature, []byte(exampleBody), sent))
+	assert.False(t, Verify(exampleSecret, exampleTimestamp, exampleSignature, []byte(exampleBody), sent.Add(10*time.Minute)))
+}
+
+func TestParseCommand(t *testing.T) {
+	cmd, err := ParseCommand([]byte("command=%2Freview&text=+reassign+pr-1+&user_id=U2CERLKJA&user_name=roadrunner&team_id=T1DC2JH3J"))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Command{
+		Command: "/review",
+		Text:    "reassign pr-1",
+		UserID:  "U2CERLKJA",
+		TeamID:  "T1DC2JH3J",
+	}, cmd)
+
+	_, err = ParseCommand([]byte("command=%2Freview&text=mine"))
+	assert.Error(t, err)
+}
+
+func TestMention(t *testing.T) {
+	assert.Equal(t, "<@U2CERLKJA>", Mention("U2CERLKJA"))
+	assert.Equal(t, "<@W0123ABC>", Mention("@W0123ABC"))
+	assert.Equal(t, "@roadrunner", Mention("roadrunner"))
+	assert.Equal(t, "@roadrunner", Mention("@roadrunner"))
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "Fix &lt;login&gt; &amp; logout", Escape("Fix <login> & logout"))
+}
+
+func TestHTTP_Post(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(status)
+		w.Write([]byte("no_text"))
+	}))
+	defer server.Close()
+
+	poster := NewHTTP(time.Second)
+
+	t.Run("posted", func(t *testing.T) {
+		err := poster.Post(context.Background(), server.URL+"/services/T1/B2/x", &Message{Text: "hi <@U2CERLKJA>"})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, got.Method)
+		assert.Equal(t, "/services/T1/B2/x", got.URL.Path)
+		assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+		var msg Message
+		require.NoError(t, json.Unmarshal(body, &msg))
+		assert.Equal(t, "hi <@U2CERLKJA>", msg.Text)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		status = http.StatusBadRequest
+
+		err := poster.Post(context.Background(), server.URL, &Message{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no_text")
+	})
+}
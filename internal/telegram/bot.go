@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"Pull-Requests-master/package/logger"
+	"context"
+	"time"
+)
+
+// retryDelay is how long the bot waits after a failed poll.
+const retryDelay = 5 * time.Second
+
+// Handler answers a message sent to the bot; an empty reply sends nothing.
+type Handler interface {
+	HandleTelegramMessage(msg *Message) string
+}
+
+// Bot long polls the Bot API and replies to every message through the handler.
+type Bot struct {
+	client  *Client
+	handler Handler
+	log     *logger.Logger
+	wait    time.Duration
+}
+
+func NewBot(client *Client, handler Handler, log *logger.Logger, wait time.Duration) *Bot {
+	if wait <= 0 {
+		wait = 30 * time.Second
+	}
+	return &Bot{
+		client:  client,
+		handler: handler,
+		log:     log,
+		wait:    wait,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (b *Bot) Run(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.client.GetUpdates(ctx, offset, b.wait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.log.Errorf("failed to get telegram updates: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+			reply := b.handler.HandleTelegramMessage(update.Message)
+			if reply == "" {
+				continue
+			}
+			if err := b.client.SendMessage(ctx, update.Message.Chat.ID, reply); err != nil {
+				b.log.Errorf("failed to reply to telegram chat %d: %v", update.Message.Chat.ID, err)
+			}
+		}
+	}
+}
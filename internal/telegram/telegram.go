@@ -0,0 +1,143 @@
+// Package telegram is a minimal Telegram Bot API client and a long polling bot.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the Bot API of telegram.org; a self-hosted Bot API server can be used instead.
+const DefaultAPIURL = "https://api.telegram.org"
+
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Chat types the bot tells apart.
+const (
+	ChatPrivate = "private"
+)
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// Client calls the Bot API methods the bot needs.
+type Client struct {
+	baseURL string
+	token   string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewClient talks to the Bot API at baseURL; timeout bounds every call on top of the
+// long polling wait.
+func NewClient(baseURL string, token string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// GetUpdates waits up to wait for updates after offset, the last update id seen plus one.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, wait time.Duration) ([]*Update, error) {
+	var updates []*Update
+	err := c.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(wait / time.Second),
+		"allowed_updates": []string{"message"},
+	}, wait, &updates)
+	return updates, err
+}
+
+// SendMessage posts plain text to the chat.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	}, 0, nil)
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, wait time.Duration, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, wait+c.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The url carries the token, keep it out of the logs.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		Description string          `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s: %s: %w", method, resp.Status, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("%s: %s: %s", method, resp.Status, envelope.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+// ParseCommand splits "/command@bot arg..." into the command without the slash and the
+// bot name, and its arguments. ok is false for text that isn't a command.
+func ParseCommand(text string) (command string, args []string, ok bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil, false
+	}
+	command = strings.TrimPrefix(fields[0], "/")
+	if i := strings.Index(command, "@"); i >= 0 {
+		command = command[:i]
+	}
+	return strings.ToLower(command), fields[1:], command != ""
+}
@@ -0,0 +1,141 @@
+package telegram
+
+import (
+	"Pull-Requests-master/package/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// botAPI stands in for the Bot API: it hands out the queued updates once and records
+// the messages sent.
+type botAPI struct {
+	mu      sync.Mutex
+	updates []*Update
+	offsets []int64
+	sent    []map[string]interface{}
+}
+
+func (a *botAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var params map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&params)
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/botT0KEN/getUpdates"):
+		a.offsets = append(a.offsets, int64(params["offset"].(float64)))
+		updates := a.updates
+		a.updates = nil
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": updates})
+	case strings.HasSuffix(r.URL.Path, "/botT0KEN/sendMessage"):
+		a.sent = append(a.sent, params)
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": map[string]interface{}{}})
+	default:
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"ok":false,"error_code":401,"description":"Unauthorized"}`)
+	}
+}
+
+func (a *botAPI) sentMessages() []map[string]interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]map[string]interface{}(nil), a.sent...)
+}
+
+type echoHandler struct{}
+
+func (echoHandler) HandleTelegramMessage(msg *Message) string {
+	if msg.Text == "/quiet" {
+		return ""
+	}
+	return "got " + msg.Text + " from " + msg.From.Username
+}
+
+func TestClient(t *testing.T) {
+	api := &botAPI{updates: []*Update{
+		{UpdateID: 7, Message: &Message{MessageID: 1, From: &User{ID: 42, Username: "bob"}, Chat: Chat{ID: 42, Type: ChatPrivate}, Text: "/reviews"}},
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "T0KEN", time.Second)
+
+	updates, err := client.GetUpdates(context.Background(), 5, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, int64(7), updates[0].UpdateID)
+	assert.Equal(t, "bob", updates[0].Message.From.Username)
+	assert.Equal(t, ChatPrivate, updates[0].Message.Chat.Type)
+	assert.Equal(t, []int64{5}, api.offsets)
+
+	require.NoError(t, client.SendMessage(context.Background(), 42, "привет"))
+	assert.Equal(t, []map[string]interface{}{{"chat_id": float64(42), "text": "привет"}}, api.sentMessages())
+
+	err = NewClient(server.URL, "wrong", time.Second).SendMessage(context.Background(), 42, "x")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unauthorized")
+	assert.NotContains(t, err.Error(), "wrong")
+}
+
+func TestBot_Run(t *testing.T) {
+	api := &botAPI{updates: []*Update{
+		{UpdateID: 10, Message: &Message{From: &User{Username: "bob"}, Chat: Chat{ID: 42}, Text: "/reviews"}},
+		{UpdateID: 11},
+		{UpdateID: 12, Message: &Message{From: &User{Username: "bob"}, Chat: Chat{ID: 42}, Text: "/quiet"}},
+		{UpdateID: 13, Message: &Message{From: &User{Username: "ann"}, Chat: Chat{ID: 43}, Text: "/active"}},
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	log, _ := test.NewNullLogger()
+	bot := NewBot(NewClient(server.URL, "T0KEN", time.Second), echoHandler{}, &logger.Logger{Logger: log}, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bot.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return len(api.sentMessages()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		return len(api.offsets) > 1 && api.offsets[len(api.offsets)-1] == 14
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []map[string]interface{}{
+		{"chat_id": float64(42), "text": "got /reviews from bob"},
+		{"chat_id": float64(43), "text": "got /active from ann"},
+	}, api.sentMessages())
+	assert.Equal(t, int64(0), api.offsets[0])
+}
+
+func TestParseCommand(t *testing.T) {
+	command, args, ok := ParseCommand("/Reassign@review_bot pr-1")
+	assert.True(t, ok)
+	assert.Equal(t, "reassign", command)
+	assert.Equal(t, []string{"pr-1"}, args)
+
+	command, args, ok = ParseCommand("  /reviews  ")
+	assert.True(t, ok)
+	assert.Equal(t, "reviews", command)
+	assert.Empty(t, args)
+
+	_, _, ok = ParseCommand("hello")
+	assert.False(t, ok)
+	_, _, ok = ParseCommand("/")
+	assert.False(t, ok)
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GitHubPullRequestEvent is the part of a GitHub "pull_request" event the service reads.
+type GitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifyGitHub checks the X-Hub-Signature-256 header, "sha256=" followed by the hex
+// HMAC of the body keyed with the webhook secret.
+func VerifyGitHub(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func ParseGitHub(body []byte) (*GitHubPullRequestEvent, error) {
+	var event GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid pull_request event: %v", err)
+	}
+	if event.Number == 0 || event.Repository.FullName == "" {
+		return nil, fmt.Errorf("pull_request event without number or repository")
+	}
+	return &event, nil
+}
+
+// Event maps the GitHub action onto the service: drafts are left alone until they are
+// ready for review, and closing counts as a merge only when the pull request was merged.
+func (e *GitHubPullRequestEvent) Event() *Event {
+	event := &Event{Action: ActionIgnore}
+	event.PR.ID = fmt.Sprintf("%s#%d", e.Repository.FullName, e.Number)
+	event.PR.Name = e.PullRequest.Title
+	event.PR.AuthorID = e.PullRequest.User.Login
+	event.PR.Repository = e.Repository.FullName
+	for _, label := range e.PullRequest.Labels {
+		event.PR.Labels = append(event.PR.Labels, label.Name)
+	}
+
+	switch e.Action {
+	case "opened":
+		if !e.PullRequest.Draft {
+			event.Action = ActionOpen
+		}
+	case "ready_for_review":
+		event.Action = ActionOpen
+	case "reopened":
+		event.Action = ActionReopen
+	case "closed":
+		event.Action = ActionClose
+		if e.PullRequest.Merged {
+			event.Action = ActionMerge
+		}
+	}
+	return event
+}
+
+// GitHubReviewEvent is the part of a GitHub "pull_request_review" event the service reads.
+type GitHubReviewEvent struct {
+	Action string `json:"action"`
+	Review struct {
+		State       string    `json:"state"`
+		SubmittedAt time.Time `json:"submitted_at"`
+		User        struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"review"`
+	PullRequest struct {
+		Number int `json:"number"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func ParseGitHubReview(body []byte) (*GitHubReviewEvent, error) {
+	var event GitHubReviewEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid pull_request_review event: %v", err)
+	}
+	if event.PullRequest.Number == 0 || event.Repository.FullName == "" || event.Review.User.Login == "" {
+		return nil, fmt.Errorf("pull_request_review event without number, repository or reviewer")
+	}
+	return &event, nil
+}
+
+// Verdict maps the GitHub review state onto a verdict; plain comments and edits carry none.
+func (e *GitHubReviewEvent) Verdict() *Review {
+	review := &Review{
+		PullRequestID: fmt.Sprintf("%s#%d", e.Repository.FullName, e.PullRequest.Number),
+		Reviewer:      e.Review.User.Login,
+		SubmittedAt:   e.Review.SubmittedAt,
+	}
+
+	switch {
+	case e.Action == "dismissed":
+		review.Verdict = domain.VerdictDismissed
+	case e.Action != "submitted":
+	case strings.EqualFold(e.Review.State, "approved"):
+		review.Verdict = domain.VerdictApproved
+	case strings.EqualFold(e.Review.State, "changes_requested"):
+		review.Verdict = domain.VerdictChangesRequested
+	}
+	return review
+}
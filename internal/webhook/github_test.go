@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitHub(t *testing.T) {
+	body := fixture(t, "github_opened.json")
+
+	assert.True(t, VerifyGitHub("s3cret", body, sign("s3cret", body)))
+	assert.False(t, VerifyGitHub("s3cret", body, sign("other", body)))
+	assert.False(t, VerifyGitHub("s3cret", append(body, ' '), sign("s3cret", body)))
+	assert.False(t, VerifyGitHub("s3cret", body, "sha1=abc"))
+	assert.False(t, VerifyGitHub("s3cret", body, "sha256=not-hex"))
+	assert.False(t, VerifyGitHub("", body, sign("", body)))
+}
+
+func TestParseGitHub(t *testing.T) {
+	t.Run("opened", func(t *testing.T) {
+		parsed, err := ParseGitHub(fixture(t, "github_opened.json"))
+		require.NoError(t, err)
+
+		event := parsed.Event()
+		assert.Equal(t, ActionOpen, event.Action)
+		assert.Equal(t, "acme/backend-api#42", event.PR.ID)
+		assert.Equal(t, "Add search index", event.PR.Name)
+		assert.Equal(t, "octo-alice", event.PR.AuthorID)
+		assert.Equal(t, "acme/backend-api", event.PR.Repository)
+		assert.Equal(t, []string{"db", "Security"}, event.PR.Labels)
+	})
+
+	t.Run("draft is ignored until ready", func(t *testing.T) {
+		parsed, err := ParseGitHub(fixture(t, "github_opened_draft.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionIgnore, parsed.Event().Action)
+
+		parsed.Action = "ready_for_review"
+		assert.Equal(t, ActionOpen, parsed.Event().Action)
+	})
+
+	t.Run("closed and merged", func(t *testing.T) {
+		parsed, err := ParseGitHub(fixture(t, "github_closed_merged.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionMerge, parsed.Event().Action)
+
+		parsed.PullRequest.Merged = false
+		assert.Equal(t, ActionClose, parsed.Event().Action)
+	})
+
+	t.Run("reopened", func(t *testing.T) {
+		parsed, err := ParseGitHub(fixture(t, "github_reopened.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionReopen, parsed.Event().Action)
+	})
+
+	t.Run("other actions", func(t *testing.T) {
+		parsed, err := ParseGitHub(fixture(t, "github_opened.json"))
+		require.NoError(t, err)
+
+		parsed.Action = "labeled"
+		assert.Equal(t, ActionIgnore, parsed.Event().Action)
+	})
+
+	t.Run("not a pull request event", func(t *testing.T) {
+		_, err := ParseGitHub([]byte(`{"zen": "Keep it logically awesome.", "hook_id": 1}`))
+		assert.Error(t, err)
+
+		_, err = ParseGitHub([]byte(`{`))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseGitHubReview(t *testing.T) {
+	t.Run("approved", func(t *testing.T) {
+		parsed, err := ParseGitHubReview(fixture(t, "github_review_approved.json"))
+		require.NoError(t, err)
+
+		review := parsed.Verdict()
+		assert.Equal(t, "acme/backend-api#42", review.PullRequestID)
+		assert.Equal(t, "octo-bob", review.Reviewer)
+		assert.Equal(t, domain.VerdictApproved, review.Verdict)
+		assert.Equal(t, time.Date(2025, 9, 2, 14, 3, 11, 0, time.UTC), review.SubmittedAt)
+	})
+
+	t.Run("changes requested", func(t *testing.T) {
+		parsed, err := ParseGitHubReview(fixture(t, "github_review_approved.json"))
+		require.NoError(t, err)
+
+		parsed.Review.State = "changes_requested"
+		assert.Equal(t, domain.VerdictChangesRequested, parsed.Verdict().Verdict)
+	})
+
+	t.Run("dismissed", func(t *testing.T) {
+		parsed, err := ParseGitHubReview(fixture(t, "github_review_dismissed.json"))
+		require.NoError(t, err)
+
+		review := parsed.Verdict()
+		assert.Equal(t, "octo-carol", review.Reviewer)
+		assert.Equal(t, domain.VerdictDismissed, review.Verdict)
+	})
+
+	t.Run("comments and edits carry no verdict", func(t *testing.T) {
+		parsed, err := ParseGitHubReview(fixture(t, "github_review_approved.json"))
+		require.NoError(t, err)
+
+		parsed.Review.State = "commented"
+		assert.Empty(t, parsed.Verdict().Verdict)
+
+		parsed.Review.State = "approved"
+		parsed.Action = "edited"
+		assert.Empty(t, parsed.Verdict().Verdict)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := ParseGitHubReview([]byte(`{"action": "submitted"`))
+		assert.Error(t, err)
+
+		_, err = ParseGitHubReview([]byte(`{"action": "submitted", "pull_request": {"number": 1}}`))
+		assert.Error(t, err)
+	})
+}
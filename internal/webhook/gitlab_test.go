@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyGitLab(t *testing.T) {
+	assert.True(t, VerifyGitLab("t0ken", "t0ken"))
+	assert.False(t, VerifyGitLab("t0ken", "other"))
+	assert.False(t, VerifyGitLab("t0ken", ""))
+	assert.False(t, VerifyGitLab("", ""))
+}
+
+func TestParseGitLab(t *testing.T) {
+	t.Run("open", func(t *testing.T) {
+		parsed, err := ParseGitLab(fixture(t, "gitlab_open.json"))
+		require.NoError(t, err)
+
+		event := parsed.Event()
+		assert.Equal(t, ActionOpen, event.Action)
+		assert.Equal(t, "payments/billing-service!7", event.PR.ID)
+		assert.Equal(t, "Retry failed charges", event.PR.Name)
+		assert.Equal(t, "alice.smith", event.PR.AuthorID)
+		assert.Equal(t, "payments/billing-service", event.PR.Repository)
+		assert.Equal(t, []string{"payments", "DB"}, event.PR.Labels)
+	})
+
+	t.Run("draft opens on the update that marks it ready", func(t *testing.T) {
+		parsed, err := ParseGitLab(fixture(t, "gitlab_open_draft.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionIgnore, parsed.Event().Action)
+
+		parsed.ObjectAttributes.Action = "update"
+		assert.Equal(t, ActionIgnore, parsed.Event().Action)
+
+		parsed.ObjectAttributes.Draft = false
+		parsed.ObjectAttributes.WorkInProgress = false
+		event := parsed.Event()
+		assert.Equal(t, ActionOpen, event.Action)
+		assert.Equal(t, "bob", event.PR.AuthorID)
+	})
+
+	t.Run("author is not the user who acted", func(t *testing.T) {
+		parsed, err := ParseGitLab(fixture(t, "gitlab_merge.json"))
+		require.NoError(t, err)
+		event := parsed.Event()
+		assert.Empty(t, event.PR.AuthorID)
+		assert.Equal(t, 17, event.AuthorHostID)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		parsed, err := ParseGitLab(fixture(t, "gitlab_merge.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionMerge, parsed.Event().Action)
+	})
+
+	t.Run("close and reopen", func(t *testing.T) {
+		parsed, err := ParseGitLab(fixture(t, "gitlab_close.json"))
+		require.NoError(t, err)
+		assert.Equal(t, ActionClose, parsed.Event().Action)
+
+		parsed.ObjectAttributes.Action = "reopen"
+		assert.Equal(t, ActionReopen, parsed.Event().Action)
+
+		parsed.ObjectAttributes.Action = "approved"
+		assert.Equal(t, ActionIgnore, parsed.Event().Action)
+	})
+
+	t.Run("not a merge request event", func(t *testing.T) {
+		_, err := ParseGitLab([]byte(`{"object_kind": "push", "project": {"path_with_namespace": "a/b"}}`))
+		assert.Error(t, err)
+
+		_, err = ParseGitLab([]byte(`{"object_kind": "merge_request"}`))
+		assert.Error(t, err)
+	})
+}
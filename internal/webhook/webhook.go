@@ -0,0 +1,37 @@
+// Package webhook turns code host notifications into pull request events of the service.
+package webhook
+
+import (
+	"Pull-Requests-master/internal/domain"
+	"time"
+)
+
+// Actions the service takes on a pull request event.
+const (
+	ActionOpen   = "open"
+	ActionMerge  = "merge"
+	ActionClose  = "close"
+	ActionReopen = "reopen"
+	ActionReview = "review"
+	// ActionIgnore is for events that don't change what the service tracks.
+	ActionIgnore = "ignore"
+)
+
+// Event is a change of a pull request on the code host, in the service's terms.
+// PR.AuthorID is the code host login until the service maps it to a user id.
+type Event struct {
+	Action string
+	PR     domain.NewPullRequest
+	// AuthorHostID is the numeric code host id of the author when the event doesn't name
+	// them; PR.AuthorID is empty then.
+	AuthorHostID int
+}
+
+// Review is a verdict given on the code host. Reviewer is the code host login until the
+// service maps it to a user id; an empty Verdict means the review is ignored.
+type Review struct {
+	PullRequestID string
+	Reviewer      string
+	Verdict       string
+	SubmittedAt   time.Time
+}
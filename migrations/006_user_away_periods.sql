@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS user_away_periods (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    started BOOLEAN NOT NULL DEFAULT FALSE,
+    finished BOOLEAN NOT NULL DEFAULT FALSE,
+    deactivated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_user_away_periods_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_away_periods_range CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_away_periods_user ON user_away_periods(user_id, starts_at, ends_at);
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_start varchar(5) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_end varchar(5) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_days INT[] NOT NULL DEFAULT '{}';
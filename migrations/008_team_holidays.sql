@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS team_holidays (
+    team_name VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (team_name, day),
+    CONSTRAINT fk_team_holidays_team
+    FOREIGN KEY (team_name)
+    REFERENCES teams(name) ON DELETE CASCADE
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS code_owners (
+    repository VARCHAR(255) PRIMARY KEY,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS changed_files TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    name VARCHAR(255) PRIMARY KEY,
+    reviewer_count INT NOT NULL DEFAULT 0,
+    strategy VARCHAR(50) NOT NULL DEFAULT '',
+    required_approvals INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS repository_teams (
+    repository VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY (repository, team_name),
+    CONSTRAINT fk_repository_teams_repository
+    FOREIGN KEY (repository)
+    REFERENCES repositories(name) ON DELETE CASCADE,
+    CONSTRAINT fk_repository_teams_team
+    FOREIGN KEY (team_name)
+    REFERENCES teams(name) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository ON pull_requests(repository);
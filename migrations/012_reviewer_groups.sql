@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS reviewer_groups (
+    name VARCHAR(255) PRIMARY KEY,
+    team_name VARCHAR(255) NOT NULL DEFAULT '',
+    repository VARCHAR(255) NOT NULL DEFAULT '',
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    members TEXT[] NOT NULL DEFAULT '{}',
+    count INT NOT NULL DEFAULT 1,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT reviewer_groups_count_check CHECK (count > 0)
+);
+
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS required_group VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS require_senior BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS no_two_juniors BOOLEAN NOT NULL DEFAULT FALSE;
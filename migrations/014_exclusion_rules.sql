@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS exclusion_rules (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    members TEXT[] NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT exclusion_rules_kind_check CHECK (kind IN ('not_together', 'no_cross_review'))
+);
@@ -0,0 +1 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS co_author_ids TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS reviewer_assignments (
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    strategy VARCHAR(50) NOT NULL DEFAULT '',
+    rule VARCHAR(50) NOT NULL,
+    rule_detail TEXT NOT NULL DEFAULT '',
+    fallback BOOLEAN NOT NULL DEFAULT FALSE,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    replaced_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_reviewer_assignments_pr
+    FOREIGN KEY (pr_id)
+    REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignments_pr ON reviewer_assignments (pr_id);
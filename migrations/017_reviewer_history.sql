@@ -0,0 +1,8 @@
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS unassigned_at TIMESTAMPTZ;
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS replaced_by VARCHAR(255) NOT NULL DEFAULT '';
+
+-- A reviewer may come back to a pull request after being replaced, so only the current
+-- assignment has to be unique.
+ALTER TABLE pr_reviewrs DROP CONSTRAINT IF EXISTS pr_reviewrs_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pr_reviewrs_current ON pr_reviewrs (pr_id, user_id) WHERE unassigned_at IS NULL;
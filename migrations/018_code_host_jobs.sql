@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS code_host_jobs (
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    reviewers TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT code_host_jobs_action_check CHECK (action IN ('request', 'remove')),
+    CONSTRAINT fk_code_host_jobs_pr
+    FOREIGN KEY (pr_id)
+    REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_code_host_jobs_due ON code_host_jobs (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+-- Reviews come from the code host, also from people who were never assigned.
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS assigned BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS verdict VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE pr_reviewrs ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
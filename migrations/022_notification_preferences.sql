@@ -0,0 +1,12 @@
+-- Which notifications a user gets and through which channels; users without a row get
+-- every notification by email.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(255) PRIMARY KEY,
+    channels TEXT[] NOT NULL,
+    events TEXT[] NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_notification_preferences_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+-- The private Telegram chat of a user with the bot; a chat belongs to one user.
+CREATE TABLE IF NOT EXISTS telegram_links (
+    user_id VARCHAR(255) PRIMARY KEY,
+    chat_id BIGINT NOT NULL UNIQUE,
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_telegram_links_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
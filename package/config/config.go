@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -24,6 +25,118 @@ type Config struct {
 		Level string `yaml:"level"`
 		Path  string `yaml:"path"`
 	} `yaml:"logger"`
+
+	Scheduler struct {
+		Enabled        bool          `yaml:"enabled"`
+		Interval       time.Duration `yaml:"interval"`
+		ReassignOnAway bool          `yaml:"reassign_on_away"`
+	} `yaml:"scheduler"`
+
+	Assignment struct {
+		Strategy string `yaml:"strategy"`
+		// MatchLabels requires a reviewer with a matching skill for every PR label.
+		MatchLabels bool `yaml:"match_labels"`
+		// Defaults for users without their own time zone or working hours.
+		TimeZone  string `yaml:"time_zone"`
+		WorkStart string `yaml:"work_start"`
+		WorkEnd   string `yaml:"work_end"`
+		// ReviewSLA is counted in working time, e.g. "16h" is two working days.
+		ReviewSLA time.Duration `yaml:"review_sla"`
+		// PairingWindow is how far back the diversity strategy looks at who reviewed whom.
+		PairingWindow time.Duration `yaml:"pairing_window"`
+	} `yaml:"assignment"`
+
+	Webhooks struct {
+		GitHub struct {
+			// Secret is the webhook secret; GITHUB_WEBHOOK_SECRET overrides it.
+			Secret string `yaml:"secret"`
+			// Users maps GitHub logins to user ids.
+			Users map[string]string `yaml:"users"`
+		} `yaml:"github"`
+		GitLab struct {
+			// Token is the webhook secret token; GITLAB_WEBHOOK_TOKEN overrides it.
+			Token string `yaml:"token"`
+			// Users maps GitLab usernames to user ids.
+			Users map[string]string `yaml:"users"`
+			// UserIDs maps numeric GitLab user ids to user ids, for authors of merge
+			// requests someone else acted on.
+			UserIDs map[int]string `yaml:"user_ids"`
+		} `yaml:"gitlab"`
+	} `yaml:"webhooks"`
+
+	CodeHost struct {
+		GitHub struct {
+			// PushReviewers requests the assigned reviewers on GitHub pull requests.
+			PushReviewers bool `yaml:"push_reviewers"`
+			// BaseURL is the REST API, https://<host>/api/v3 for GitHub Enterprise.
+			BaseURL string `yaml:"base_url"`
+			// Token needs pull request write access; GITHUB_TOKEN overrides it.
+			Token       string `yaml:"token"`
+			MaxAttempts int    `yaml:"max_attempts"`
+			// RetryInterval is how often the queue of reviewer changes is retried.
+			RetryInterval time.Duration `yaml:"retry_interval"`
+		} `yaml:"github"`
+	} `yaml:"code_host"`
+
+	// Outbound is how events are posted to webhook subscriptions.
+	Outbound struct {
+		Enabled     bool          `yaml:"enabled"`
+		Timeout     time.Duration `yaml:"timeout"`
+		MaxAttempts int           `yaml:"max_attempts"`
+		// RetryInterval is how often the queue of deliveries is retried.
+		RetryInterval time.Duration `yaml:"retry_interval"`
+	} `yaml:"outbound_webhooks"`
+
+	// Outbox is where the relay publishes domain events; an empty sink only stores them.
+	Outbox struct {
+		Sink      string        `yaml:"sink"`
+		Interval  time.Duration `yaml:"interval"`
+		Retention time.Duration `yaml:"retention"`
+		Timeout   time.Duration `yaml:"timeout"`
+		HTTP      struct {
+			URL string `yaml:"url"`
+		} `yaml:"http"`
+		NATS struct {
+			// URL is nats://[user:pass@]host:port; OUTBOX_NATS_URL overrides it.
+			URL     string `yaml:"url"`
+			Subject string `yaml:"subject"`
+		} `yaml:"nats"`
+	} `yaml:"outbox"`
+
+	// Notifications are sent to users about their reviews through the enabled channels.
+	Notifications struct {
+		Email struct {
+			Enabled  bool   `yaml:"enabled"`
+			Host     string `yaml:"host"`
+			Port     int    `yaml:"port"`
+			Username string `yaml:"username"`
+			// Password is for the SMTP login; SMTP_PASSWORD overrides it.
+			Password string        `yaml:"password"`
+			From     string        `yaml:"from"`
+			StartTLS bool          `yaml:"starttls"`
+			Timeout  time.Duration `yaml:"timeout"`
+		} `yaml:"email"`
+	} `yaml:"notifications"`
+
+	Slack struct {
+		// Enabled posts new reviewers and reassignments to the channels in Webhooks.
+		Enabled bool          `yaml:"enabled"`
+		Timeout time.Duration `yaml:"timeout"`
+		// Webhooks maps team names to the incoming webhook URL of their channel.
+		Webhooks map[string]string `yaml:"webhooks"`
+		// SigningSecret verifies the /review command; SLACK_SIGNING_SECRET overrides it.
+		SigningSecret string `yaml:"signing_secret"`
+	} `yaml:"slack"`
+
+	// Telegram runs the bot by long polling the Bot API at APIURL.
+	Telegram struct {
+		Enabled bool   `yaml:"enabled"`
+		APIURL  string `yaml:"api_url"`
+		// Token is the bot token; TELEGRAM_BOT_TOKEN overrides it.
+		Token       string        `yaml:"token"`
+		PollTimeout time.Duration `yaml:"poll_timeout"`
+		Timeout     time.Duration `yaml:"timeout"`
+	} `yaml:"telegram"`
 }
 
 func GetConfig() (*Config, error) {
@@ -44,5 +157,27 @@ func GetConfig() (*Config, error) {
 	config.DB.Password = os.Getenv("DB_PASSWORD")
 	config.DB.SSLMode = os.Getenv("DB_SSLMODE")
 
+	if secret := os.Getenv("GITHUB_WEBHOOK_SECRET"); secret != "" {
+		config.Webhooks.GitHub.Secret = secret
+	}
+	if token := os.Getenv("GITLAB_WEBHOOK_TOKEN"); token != "" {
+		config.Webhooks.GitLab.Token = token
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		config.CodeHost.GitHub.Token = token
+	}
+	if url := os.Getenv("OUTBOX_NATS_URL"); url != "" {
+		config.Outbox.NATS.URL = url
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		config.Notifications.Email.Password = password
+	}
+	if secret := os.Getenv("SLACK_SIGNING_SECRET"); secret != "" {
+		config.Slack.SigningSecret = secret
+	}
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		config.Telegram.Token = token
+	}
+
 	return config, nil
 }